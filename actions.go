@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/rskv-p/mini/codec"
 	"github.com/rskv-p/mini/router"
@@ -16,10 +18,38 @@ import (
 
 type contextKey string
 
-const ContextIDKey contextKey = "contextID"
+const (
+	ContextIDKey  contextKey = "contextID"
+	ActionNameKey contextKey = "action"
+	TraceIDKey    contextKey = "traceID"
+
+	// PayloadSizeKey holds the size in bytes of the raw request payload.
+	PayloadSizeKey contextKey = "payloadSize"
+)
 
 func ContextIDFrom(ctx context.Context) string {
-	if v := ctx.Value(ContextIDKey); v != nil {
+	return stringFrom(ctx, ContextIDKey)
+}
+
+// ActionNameFrom returns the name of the action being executed.
+func ActionNameFrom(ctx context.Context) string {
+	return stringFrom(ctx, ActionNameKey)
+}
+
+// TraceIDFrom returns the trace ID of the incoming message.
+func TraceIDFrom(ctx context.Context) string {
+	return stringFrom(ctx, TraceIDKey)
+}
+
+// PayloadSizeFrom returns the raw payload size of the incoming request, or
+// 0 when unknown (e.g. a handler called directly).
+func PayloadSizeFrom(ctx context.Context) int {
+	n, _ := ctx.Value(PayloadSizeKey).(int)
+	return n
+}
+
+func stringFrom(ctx context.Context, key contextKey) string {
+	if v := ctx.Value(key); v != nil {
 		if s, ok := v.(string); ok {
 			return s
 		}
@@ -38,6 +68,7 @@ type InputSchemaField struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	Required bool   `json:"required"`
+	Default  any    `json:"default,omitempty"`
 }
 
 type IAction interface {
@@ -59,6 +90,9 @@ func (s *Service) RegisterAction(name string, schema []InputSchemaField, fn Acti
 	if s.actions == nil {
 		s.actions = make(map[string]actionInfo)
 	}
+	if unknown := unknownTypes(schema); len(unknown) > 0 && s.logger != nil {
+		s.logger.Warn("action %s: unknown input types, values are not checked: %s", name, strings.Join(unknown, ", "))
+	}
 	s.actions[name] = actionInfo{schema: schema, handler: fn}
 }
 
@@ -99,7 +133,17 @@ func (s *Service) GetOpenAPISchemas() map[string]any {
 		required := []string{}
 		properties := map[string]any{}
 		for _, f := range info.schema {
-			properties[f.Name] = map[string]string{"type": f.Type}
+			prop := map[string]any{}
+			if typ := openAPIType(f.Type); typ != "" {
+				prop["type"] = typ
+			}
+			if format := openAPIFormat(f.Type); format != "" {
+				prop["format"] = format
+			}
+			if f.Default != nil {
+				prop["default"] = f.Default
+			}
+			properties[f.Name] = prop
 			if f.Required {
 				required = append(required, f.Name)
 			}
@@ -113,9 +157,53 @@ func (s *Service) GetOpenAPISchemas() map[string]any {
 		}
 		schemas[name] = schema
 	}
+	if s.opts.Router != nil {
+		for _, n := range s.opts.Router.Routes() {
+			if len(n.ValidationRules) > 0 {
+				mergeSchema(schemas, n.ID, n.Schema())
+			}
+		}
+	}
 	return schemas
 }
 
+// mergeSchema adds router-derived constraints to the schema for name,
+// keeping anything the action schema already declares.
+func mergeSchema(schemas map[string]any, name string, extra map[string]any) {
+	base, ok := schemas[name].(map[string]any)
+	if !ok {
+		schemas[name] = extra
+		return
+	}
+	props, _ := base["properties"].(map[string]any)
+	if props == nil {
+		props = map[string]any{}
+		base["properties"] = props
+	}
+	for field, p := range extra["properties"].(map[string]any) {
+		cur, ok := props[field].(map[string]any)
+		if !ok {
+			props[field] = p
+			continue
+		}
+		for k, v := range p.(map[string]any) {
+			if _, exists := cur[k]; !exists {
+				cur[k] = v
+			}
+		}
+	}
+	req, _ := base["required"].([]string)
+	extraReq, _ := extra["required"].([]string)
+	for _, field := range extraReq {
+		if !slices.Contains(req, field) {
+			req = append(req, field)
+		}
+	}
+	if len(req) > 0 {
+		base["required"] = req
+	}
+}
+
 // ----------------------------------------------------
 // Middleware chaining
 // ----------------------------------------------------
@@ -140,24 +228,28 @@ func (s *Service) prepareHandler(fn ActionFunc) router.Handler {
 
 		actionID := raw.GetNode()
 		body := raw.GetBodyMap()
+		ctx = context.WithValue(ctx, ActionNameKey, actionID)
+		if traceID := raw.GetString("trace_id"); traceID != "" {
+			ctx = context.WithValue(ctx, TraceIDKey, traceID)
+		}
 
 		// schema validation
 		info, ok := s.actions[actionID]
 		if ok && len(info.schema) > 0 {
-			for _, field := range info.schema {
-				val, exists := body[field.Name]
-				if field.Required && (!exists || isEmpty(val)) {
-					msg := fmt.Sprintf("missing required field: %s", field.Name)
-					s.logger.WithContext(ctxID).Warn(msg)
-
-					resp := codec.NewJsonResponse(ctxID, 400)
-					resp.SetError(errors.New(msg))
-					_ = s.Respond(resp, replyTo)
-					return &router.Error{StatusCode: 400, Message: msg}
+			if err := validateInput(info.schema, body); err != nil {
+				s.logger.WithContext(ctxID).Warn("validation failed: %v", err)
+
+				resp := codec.NewJsonResponse(ctxID, 400)
+				resp.SetError(err)
+				if verr, ok := err.(*ValidationError); ok {
+					resp.Set("fields", verr.Fields)
 				}
+				_ = s.Respond(resp, replyTo)
+				return &router.Error{StatusCode: 400, Message: err.Error()}
 			}
 		}
 
+		ctx = withInput(ctx, info.schema, body)
 		handler := chainMiddlewares(fn, s.middlewares...)
 
 		defer func() {
@@ -173,7 +265,7 @@ func (s *Service) prepareHandler(fn ActionFunc) router.Handler {
 
 		status := 200
 		if err != nil {
-			status = 500
+			status = errorStatus(err)
 		}
 		resp := codec.NewJsonResponse(ctxID, status)
 
@@ -190,6 +282,20 @@ func (s *Service) prepareHandler(fn ActionFunc) router.Handler {
 	}
 }
 
+// errorStatus returns the response status for an action error: the
+// StatusCode of a *router.Error, 400 for validation errors, else 500.
+func errorStatus(err error) int {
+	var rerr *router.Error
+	if errors.As(err, &rerr) && rerr.StatusCode != 0 {
+		return rerr.StatusCode
+	}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return 400
+	}
+	return 500
+}
+
 // ----------------------------------------------------
 // Utility
 // ----------------------------------------------------
@@ -3,13 +3,15 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
 	"github.com/rskv-p/mini/logger"
 	"github.com/rskv-p/mini/router"
+	"github.com/rskv-p/mini/transport"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,11 +21,17 @@ import (
 
 type testLogger struct {
 	fields map[string]any
+	mu     sync.Mutex
+	warns  []string
 }
 
 func (l *testLogger) Debug(msg string, args ...any) {}
 func (l *testLogger) Info(msg string, args ...any)  {}
-func (l *testLogger) Warn(msg string, args ...any)  {}
+func (l *testLogger) Warn(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, fmt.Sprintf(msg, args...))
+}
 func (l *testLogger) Error(msg string, args ...any) {}
 func (l *testLogger) WithContext(contextID string) logger.ILogger {
 	return l
@@ -78,6 +86,36 @@ func newTestService() *testService {
 	}
 }
 
+// stubTransport lets Init run without NSQ. Unused methods panic.
+type stubTransport struct {
+	transport.ITransport
+	sent       []stubPublish
+	subscribed bool
+	used       int
+}
+
+type stubPublish struct {
+	subject string
+	data    []byte
+}
+
+func (s *stubTransport) Init() error                     { return nil }
+func (s *stubTransport) Close() error                    { return nil }
+func (s *stubTransport) SetHandler(transport.MsgHandler) {}
+func (s *stubTransport) Use(transport.MiddlewareFunc)    { s.used++ }
+func (s *stubTransport) Subscribe() error                { s.subscribed = true; return nil }
+func (s *stubTransport) Unsubscribe() error              { s.subscribed = false; return nil }
+func (s *stubTransport) Publish(subject string, data []byte) error {
+	s.sent = append(s.sent, stubPublish{subject: subject, data: data})
+	return nil
+}
+
+// newInitService returns a real Service that can be Init-ed in tests.
+func newInitService(opts ...Option) *Service {
+	opts = append([]Option{Logger(&testLogger{}), Transport(&stubTransport{})}, opts...)
+	return NewService("svc", "test", opts...)
+}
+
 // Append response to internal buffer (no-op reply)
 func (s *testService) Respond(msg codec.IMessage, replyTo string) error {
 	s.Responses = append(s.Responses, msg)
@@ -96,17 +134,16 @@ func (s *testService) prepareHandler(fn ActionFunc) func(ctx context.Context, ra
 
 		info, ok := s.actions[node]
 		if ok && len(info.schema) > 0 {
-			for _, f := range info.schema {
-				v, exists := body[f.Name]
-				if f.Required && (!exists || v == nil || isEmpty(v)) {
-					msg := fmt.Sprintf("missing required field: %s", f.Name)
-					s.logger.WithContext(ctxID).Warn(msg)
-
-					resp := codec.NewJsonResponse(ctxID, 400)
-					resp.SetError(errors.New(msg))
-					_ = s.Respond(resp, replyTo)
-					return &router.Error{StatusCode: 400, Message: msg}
+			if err := validateInput(info.schema, body); err != nil {
+				s.logger.WithContext(ctxID).Warn("validation failed: %v", err)
+
+				resp := codec.NewJsonResponse(ctxID, 400)
+				resp.SetError(err)
+				if verr, ok := err.(*ValidationError); ok {
+					resp.Set("fields", verr.Fields)
 				}
+				_ = s.Respond(resp, replyTo)
+				return &router.Error{StatusCode: 400, Message: err.Error()}
 			}
 		}
 
@@ -242,3 +279,59 @@ func TestIsEmpty(t *testing.T) {
 	assert.False(t, isEmpty("non-empty"))
 	assert.False(t, isEmpty([]byte("x")))
 }
+
+func TestMessageContext_Correlation(t *testing.T) {
+	s := newTestService()
+
+	msg := codec.NewMessage("request")
+	msg.SetContextID("ctx-parent")
+	msg.SetHeader(constant.HeaderCorrelationID, "corr-42")
+
+	ctx := s.messageContext(context.Background(), msg)
+	assert.Equal(t, "ctx-parent", ContextIDFrom(ctx))
+	assert.Equal(t, "corr-42", transport.CorrelationIDFromContext(ctx))
+	assert.Equal(t, "ctx-parent", transport.CausationIDFromContext(ctx))
+}
+
+func TestOpenAPISchemas_RouterRules(t *testing.T) {
+	s := newTestService()
+	s.opts.Router = router.NewRouter()
+	s.RegisterAction("user.update", []InputSchemaField{
+		{Name: "name", Type: TypeString},
+	}, func(ctx context.Context, in map[string]any) (any, error) { return nil, nil })
+
+	s.opts.Router.Add(&router.Node{
+		ID: "user.update",
+		Handler: func(context.Context, codec.IMessage, string) *router.Error {
+			return nil
+		},
+		ValidationRules: map[string][]string{"name": {"required", "max:10"}},
+	})
+	s.opts.Router.Add(&router.Node{
+		ID: "raw.route",
+		Handler: func(context.Context, codec.IMessage, string) *router.Error {
+			return nil
+		},
+		ValidationRules: map[string][]string{"id": {"required"}},
+	})
+
+	schemas := s.GetOpenAPISchemas()
+	upd := schemas["user.update"].(map[string]any)
+	name := upd["properties"].(map[string]any)["name"].(map[string]any)
+	assert.Equal(t, "string", name["type"])
+	assert.Equal(t, 10, name["maxLength"])
+	assert.Equal(t, []string{"name"}, upd["required"])
+	assert.Contains(t, schemas, "raw.route")
+
+	out, err := s.describeAction(context.Background(), map[string]any{"id": "raw.route"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, out.(map[string]any)["required"])
+
+	out, err = s.describeAction(context.Background(), map[string]any{"id": "user.update"})
+	assert.NoError(t, err)
+	want, _ := s.opts.Router.Describe("user.update")
+	assert.Equal(t, want, out)
+
+	_, err = s.describeAction(context.Background(), map[string]any{"id": "nope"})
+	assert.ErrorIs(t, err, constant.ErrNotFound)
+}
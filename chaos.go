@@ -0,0 +1,71 @@
+// file: mini/chaos.go
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/rskv-p/mini/transport"
+)
+
+// ----------------------------------------------------
+// Chaos / fault injection (dev mode only)
+// ----------------------------------------------------
+
+// ActionChaos injects latency and errors into actions, matching rules
+// against the action name. Drop rates only apply to transport traffic.
+// EnableChaos does not install it; add it with Use for name-based rules.
+func ActionChaos(c *transport.Chaos) Middleware {
+	return func(next ActionFunc) ActionFunc {
+		return func(ctx context.Context, input map[string]any) (any, error) {
+			eff := c.Decide(ActionNameFrom(ctx))
+			if eff.Delay > 0 {
+				select {
+				case <-time.After(eff.Delay):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			if eff.Fail {
+				return nil, transport.ErrChaosInjected
+			}
+			return next(ctx, input)
+		}
+	}
+}
+
+// setupChaos installs chaos rules as transport middleware, which also
+// covers action requests. It is a no-op unless the "dev_mode" config key
+// is true, and only installs once per service.
+func (s *Service) setupChaos() {
+	if len(s.opts.Chaos) == 0 || s.chaos != nil {
+		return
+	}
+	if !s.devMode() {
+		s.logger.Warn("chaos rules ignored: dev_mode is off")
+		return
+	}
+	s.chaos = transport.NewChaos(s.opts.Chaos...)
+	s.opts.Transport.Use(s.chaos.Middleware())
+	s.logger.Warn("chaos enabled with %d rule(s)", len(s.opts.Chaos))
+}
+
+// devMode reports whether the "dev_mode" config key is set to true.
+func (s *Service) devMode() bool {
+	if s.config == nil {
+		return false
+	}
+	v, ok := s.config.Get("dev_mode")
+	if !ok {
+		return false
+	}
+	switch x := v.(type) {
+	case bool:
+		return x
+	case string:
+		b, _ := strconv.ParseBool(x)
+		return b
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// file: mini/chaos_test.go
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/rskv-p/mini/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionChaos(t *testing.T) {
+	mw := ActionChaos(transport.NewChaos(transport.ChaosRule{Subject: "pay.*", ErrorRate: 1}))
+	fn := mw(func(ctx context.Context, input map[string]any) (any, error) { return "ok", nil })
+
+	ctx := context.WithValue(context.Background(), ActionNameKey, "pay.charge")
+	_, err := fn(ctx, nil)
+	assert.ErrorIs(t, err, transport.ErrChaosInjected)
+
+	ctx = context.WithValue(context.Background(), ActionNameKey, "user.get")
+	out, err := fn(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", out)
+}
+
+func TestSetupChaos_DevModeOnly(t *testing.T) {
+	rule := transport.ChaosRule{ErrorRate: 1}
+	tr := &stubTransport{}
+
+	prod, _ := config.New()
+	s := newTestService()
+	s.config = prod
+	s.opts.Transport = tr
+	s.opts.Chaos = []transport.ChaosRule{rule}
+	s.setupChaos()
+	assert.Equal(t, 0, tr.used)
+
+	dev, _ := config.New(config.WithDefaults(map[string]any{"dev_mode": "true"}))
+	s.config = dev
+	s.setupChaos()
+	s.setupChaos()
+	assert.Equal(t, 1, tr.used)
+	assert.Empty(t, s.middlewares)
+}
@@ -0,0 +1,70 @@
+// file: mini/codec/bind.go
+package codec
+
+import "reflect"
+
+// DecodeMode controls how unknown JSON fields are handled when binding.
+type DecodeMode int
+
+const (
+	// Lenient ignores fields that have no matching struct field.
+	Lenient DecodeMode = iota
+	// Strict rejects fields that have no matching struct field.
+	Strict
+)
+
+// DecodeInto decodes the message body into a new value of type T.
+func DecodeInto[T any](msg IMessage, mode ...DecodeMode) (T, error) {
+	var out T
+	err := bindMessage(msg, &out, mode...)
+	return out, err
+}
+
+// BindBody decodes the message body into target.
+func (m *Message) BindBody(target any, mode ...DecodeMode) error {
+	return bindMessage(m, target, mode...)
+}
+
+// bindMessage decodes the current body map. RawBody is not used: it is
+// only a cache and goes stale when the map is changed in place.
+func bindMessage(msg IMessage, target any, mode ...DecodeMode) error {
+	return decodeValue(msg.GetBodyMap(), target, mode...)
+}
+
+// assignValue stores v in the value target points to when v already has a
+// compatible type. Maps and slices are deep-copied so target does not alias
+// the message body.
+func assignValue(v any, target any) bool {
+	if v == nil {
+		return false
+	}
+	dst := reflect.ValueOf(target)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() {
+		return false
+	}
+	src := reflect.ValueOf(v)
+	if !src.Type().AssignableTo(dst.Elem().Type()) {
+		return false
+	}
+	dst.Elem().Set(reflect.ValueOf(cloneValue(v)))
+	return true
+}
+
+// cloneValue deep-copies JSON-shaped maps and slices.
+func cloneValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = cloneValue(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = cloneValue(e)
+		}
+		return out
+	}
+	return v
+}
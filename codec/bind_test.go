@@ -0,0 +1,100 @@
+// file: mini/codec/bind_test.go
+package codec_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestDecodeInto(t *testing.T) {
+	msg := codec.NewMessage("user")
+	msg.Set("name", "bob")
+	msg.Set("age", 42)
+
+	u, err := codec.DecodeInto[bindUser](msg)
+	assert.NoError(t, err)
+	assert.Equal(t, bindUser{Name: "bob", Age: 42}, u)
+}
+
+func TestBindBody_StrictLenient(t *testing.T) {
+	msg := codec.NewMessage("user")
+	msg.Set("name", "bob")
+	msg.Set("extra", true)
+
+	var lenient bindUser
+	assert.NoError(t, msg.BindBody(&lenient))
+	assert.Equal(t, "bob", lenient.Name)
+
+	var strict bindUser
+	assert.Error(t, msg.BindBody(&strict, codec.Strict))
+}
+
+func TestBindBody_IgnoresRawBody(t *testing.T) {
+	msg := codec.NewMessage("user")
+	msg.Set("name", "alice")
+	assert.NoError(t, msg.UpdateRawBody())
+	msg.GetBodyMap()["name"] = "bob" // in-place change, RawBody is stale
+
+	var u bindUser
+	assert.NoError(t, msg.BindBody(&u, codec.Strict))
+	assert.Equal(t, "bob", u.Name)
+
+	wire := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal([]byte(`{"type":"user","body":{"name":"real"},"rawBody":"eyJuYW1lIjoiZmFrZSJ9"}`), wire))
+	assert.NoError(t, wire.BindBody(&u))
+	assert.Equal(t, "real", u.Name)
+}
+
+func TestSetBody_MapAndRaw(t *testing.T) {
+	src := map[string]any{"a": 1}
+	msg := codec.NewMessage("m")
+	msg.SetBody(src)
+	msg.Set("b", 2)
+	assert.NotContains(t, src, "b")
+	assert.Equal(t, int64(1), msg.GetInt("a"))
+
+	msg.SetBody(json.RawMessage(`{"name":"raw"}`))
+	assert.Equal(t, "raw", msg.GetString("name"))
+
+	msg.SetBody(bindUser{Name: "s", Age: 3})
+	assert.Equal(t, int64(3), msg.GetInt("age"))
+
+	assert.Error(t, msg.TrySetBody(json.RawMessage(`{"name":`)))
+	assert.Empty(t, msg.GetBodyMap())
+}
+
+func TestSetBody_DeepCopiesMaps(t *testing.T) {
+	src := map[string]any{"n": map[string]any{"x": 1}, "l": []any{map[string]any{"y": 2}}}
+	msg := codec.NewMessage("m")
+	assert.NoError(t, msg.TrySetBody(src))
+
+	msg.GetBodyMap()["n"].(map[string]any)["x"] = 9
+	msg.GetBodyMap()["l"].([]any)[0].(map[string]any)["y"] = 9
+	assert.Equal(t, 1, src["n"].(map[string]any)["x"])
+	assert.Equal(t, 2, src["l"].([]any)[0].(map[string]any)["y"])
+}
+
+func TestGetResult_DirectAssign(t *testing.T) {
+	msg := codec.NewMessage("m")
+	msg.SetResult(map[string]any{"k": "v"})
+
+	var out map[string]any
+	assert.NoError(t, msg.GetResult(&out))
+	assert.Equal(t, map[string]any{"k": "v"}, out)
+	out["k"] = "changed"
+	var again map[string]any
+	assert.NoError(t, msg.GetResult(&again))
+	assert.Equal(t, "v", again["k"])
+
+	var typed map[string]string
+	assert.NoError(t, msg.GetResult(&typed))
+	assert.Equal(t, "v", typed["k"])
+}
@@ -0,0 +1,386 @@
+// file: mini/codec/decode.go
+package codec
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ----------------------------------------------------
+// Direct decoding of parsed JSON values
+// ----------------------------------------------------
+
+// decodeValue stores an already-parsed JSON value (maps, slices, numbers,
+// strings, bools, nil) in target without encoding it again, following the
+// encoding/json rules for field names, tags and embedded structs. Only
+// values whose type decodes itself (json.Unmarshaler, encoding.TextUnmarshaler)
+// or fields using the ",string" tag option still go through encoding/json.
+func decodeValue(v any, target any, mode ...DecodeMode) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(target)}
+	}
+	d := decoder{strict: len(mode) > 0 && mode[0] == Strict}
+	return d.value(v, rv.Elem(), "")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+type decoder struct {
+	strict bool
+}
+
+// value decodes v into dst, which must be addressable.
+func (d decoder) value(v any, dst reflect.Value, path string) error {
+	if v == nil {
+		switch dst.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			dst.SetZero()
+		}
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return d.value(v, dst.Elem(), path)
+	}
+	if pt := dst.Addr().Type(); pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return d.viaJSON(v, dst)
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		return d.object(x, dst, path)
+	case []any:
+		return d.array(x, dst, path)
+	case string:
+		return d.str(x, dst, path)
+	case bool:
+		if dst.Kind() == reflect.Bool {
+			dst.SetBool(x)
+			return nil
+		}
+		if isEmptyInterface(dst) {
+			dst.Set(reflect.ValueOf(x))
+			return nil
+		}
+		return typeError("bool", dst, path)
+	}
+	if n := reflect.ValueOf(v); isNumberKind(n.Kind()) {
+		return d.number(n, dst, path)
+	}
+	// Go values stored with Set (structs, typed slices and maps) have no
+	// parsed form to walk.
+	return d.viaJSON(v, dst)
+}
+
+func (d decoder) object(m map[string]any, dst reflect.Value, path string) error {
+	switch dst.Kind() {
+	case reflect.Interface:
+		if !isEmptyInterface(dst) {
+			return typeError("object", dst, path)
+		}
+		dst.Set(reflect.ValueOf(cloneValue(m)))
+		return nil
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			return d.viaJSON(m, dst)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(m)))
+		}
+		for k, e := range m {
+			ev := reflect.New(dst.Type().Elem()).Elem()
+			if err := d.value(e, ev, joinPath(path, k)); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), ev)
+		}
+		return nil
+	case reflect.Struct:
+		fields := structFields(dst.Type())
+		if fields.stringOpt {
+			return d.viaJSON(m, dst)
+		}
+		for k, e := range m {
+			f, ok := fields.lookup(k)
+			if !ok {
+				if d.strict {
+					return fmt.Errorf("json: unknown field %q", k)
+				}
+				continue
+			}
+			fv, err := fieldByIndex(dst, f.index)
+			if err != nil {
+				return err
+			}
+			if !fv.CanSet() {
+				continue // promoted through an unexported embedded struct
+			}
+			if err := d.value(e, fv, joinPath(path, f.name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return typeError("object", dst, path)
+}
+
+func (d decoder) array(a []any, dst reflect.Value, path string) error {
+	switch dst.Kind() {
+	case reflect.Interface:
+		if !isEmptyInterface(dst) {
+			return typeError("array", dst, path)
+		}
+		dst.Set(reflect.ValueOf(cloneValue(a)))
+		return nil
+	case reflect.Slice:
+		out := reflect.MakeSlice(dst.Type(), len(a), len(a))
+		for i, e := range a {
+			if err := d.value(e, out.Index(i), joinPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	case reflect.Array:
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(a) {
+				dst.Index(i).SetZero()
+				continue
+			}
+			if err := d.value(a[i], dst.Index(i), joinPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return typeError("array", dst, path)
+}
+
+func (d decoder) str(s string, dst reflect.Value, path string) error {
+	switch {
+	case dst.Kind() == reflect.String:
+		dst.SetString(s)
+		return nil
+	case isEmptyInterface(dst):
+		dst.Set(reflect.ValueOf(s))
+		return nil
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(b).Convert(dst.Type()))
+		return nil
+	}
+	return typeError("string", dst, path)
+}
+
+// number stores a float64 from the wire, or an int or float stored locally
+// with Set, rejecting fractions and overflow for integer targets.
+func (d decoder) number(n reflect.Value, dst reflect.Value, path string) error {
+	var f float64
+	switch {
+	case n.CanInt():
+		f = float64(n.Int())
+	case n.CanUint():
+		f = float64(n.Uint())
+	default:
+		f = n.Float()
+	}
+	switch {
+	case isEmptyInterface(dst):
+		dst.Set(n)
+		return nil
+	case dst.CanInt():
+		i, ok := int64(0), false
+		if n.CanInt() {
+			i, ok = n.Int(), true
+		} else if n.CanUint() {
+			i, ok = int64(n.Uint()), n.Uint() <= math.MaxInt64
+		} else if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			i, ok = int64(f), true
+		}
+		if !ok || dst.OverflowInt(i) {
+			return typeError("number "+formatNumber(f), dst, path)
+		}
+		dst.SetInt(i)
+		return nil
+	case dst.CanUint():
+		u, ok := uint64(0), false
+		if n.CanUint() {
+			u, ok = n.Uint(), true
+		} else if n.CanInt() {
+			u, ok = uint64(n.Int()), n.Int() >= 0
+		} else if f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 {
+			u, ok = uint64(f), true
+		}
+		if !ok || dst.OverflowUint(u) {
+			return typeError("number "+formatNumber(f), dst, path)
+		}
+		dst.SetUint(u)
+		return nil
+	case dst.CanFloat():
+		if dst.OverflowFloat(f) {
+			return typeError("number "+formatNumber(f), dst, path)
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	return typeError("number", dst, path)
+}
+
+// viaJSON is the fallback for types that decode themselves.
+func (d decoder) viaJSON(v any, dst reflect.Value) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	if d.strict {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(dst.Addr().Interface())
+}
+
+func isEmptyInterface(v reflect.Value) bool {
+	return v.Kind() == reflect.Interface && v.NumMethod() == 0
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64 && k != reflect.Uintptr
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func typeError(what string, dst reflect.Value, path string) error {
+	return &json.UnmarshalTypeError{Value: what, Type: dst.Type(), Field: path}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// ----------------------------------------------------
+// Struct field lookup
+// ----------------------------------------------------
+
+type field struct {
+	name  string
+	index []int
+}
+
+type fieldSet struct {
+	byName    map[string]field
+	list      []field
+	stringOpt bool // some field uses ",string"; decoded by encoding/json
+}
+
+// lookup matches a key exactly first, then case-insensitively, like
+// encoding/json.
+func (s *fieldSet) lookup(key string) (field, bool) {
+	if f, ok := s.byName[key]; ok {
+		return f, true
+	}
+	for _, f := range s.list {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+var fieldCache sync.Map // reflect.Type -> *fieldSet
+
+func structFields(t reflect.Type) *fieldSet {
+	if s, ok := fieldCache.Load(t); ok {
+		return s.(*fieldSet)
+	}
+	s := &fieldSet{byName: make(map[string]field)}
+	depth := make(map[string]int)
+	collectFields(t, nil, s, depth)
+	fieldCache.Store(t, s)
+	return s
+}
+
+// collectFields walks t breadth-first through embedded structs. A
+// shallower field hides deeper ones with the same name.
+func collectFields(t reflect.Type, index []int, s *fieldSet, depth map[string]int) {
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	var next []embedded
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(append([]int(nil), index...), i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			next = append(next, embedded{t: ft, index: idx})
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.Contains(","+opts+",", ",string,") {
+			s.stringOpt = true
+		}
+		if d, seen := depth[name]; seen && d <= len(index) {
+			continue
+		}
+		depth[name] = len(index)
+		f := field{name: name, index: idx}
+		s.byName[name] = f
+		s.list = append(s.list, f)
+	}
+	for _, e := range next {
+		collectFields(e.t, e.index, s, depth)
+	}
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded
+// pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("json: cannot set embedded pointer to unexported struct: %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
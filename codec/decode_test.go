@@ -0,0 +1,88 @@
+// file: mini/codec/decode_test.go
+package codec_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeBase struct {
+	ID      string `json:"id"`
+	Created time.Time
+}
+
+type decodeAddr struct {
+	City string `json:"city"`
+}
+
+type decodeOrder struct {
+	decodeBase
+	Qty     uint8             `json:"qty"`
+	Price   float32           `json:"price"`
+	Tags    []string          `json:"tags"`
+	Addr    *decodeAddr       `json:"addr"`
+	Lines   []decodeAddr      `json:"lines"`
+	Attrs   map[string]int    `json:"attrs"`
+	Extra   any               `json:"extra"`
+	Blob    []byte            `json:"blob"`
+	Pair    [2]int            `json:"pair"`
+	Labels  map[string]string `json:"labels"`
+	Skipped string            `json:"-"`
+}
+
+func TestDecodeInto_MatchesEncodingJSON(t *testing.T) {
+	raw := `{"id":"o-1","created":"2024-05-01T10:00:00Z","qty":3,"price":1.5,
+		"tags":["a","b"],"addr":{"city":"Oslo"},"lines":[{"CITY":"Rome"}],
+		"attrs":{"x":1},"extra":{"k":[1,"v"]},"blob":"aGk=","pair":[7],
+		"labels":null,"Skipped":"no"}`
+
+	msg := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal([]byte(`{"body":`+raw+`}`), msg))
+	got, err := codec.DecodeInto[decodeOrder](msg)
+	assert.NoError(t, err)
+
+	var want decodeOrder
+	assert.NoError(t, json.Unmarshal([]byte(raw), &want))
+	assert.Equal(t, want, got)
+	assert.Equal(t, "Oslo", got.Addr.City)
+	assert.Equal(t, []byte("hi"), got.Blob)
+}
+
+func TestDecodeInto_LocalValues(t *testing.T) {
+	msg := codec.NewMessage("")
+	msg.Set("tags", []string{"a"})
+	msg.Set("qty", 4)
+	msg.Set("addr", decodeAddr{City: "Paris"})
+
+	got, err := codec.DecodeInto[decodeOrder](msg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, got.Tags)
+	assert.Equal(t, uint8(4), got.Qty)
+	assert.Equal(t, "Paris", got.Addr.City)
+}
+
+func TestDecodeInto_Errors(t *testing.T) {
+	var typeErr *json.UnmarshalTypeError
+
+	msg := codec.NewMessage("")
+	msg.Set("qty", 300.0)
+	_, err := codec.DecodeInto[decodeOrder](msg)
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "qty", typeErr.Field)
+
+	msg = codec.NewMessage("")
+	msg.Set("qty", 1.5)
+	_, err = codec.DecodeInto[decodeOrder](msg)
+	assert.ErrorAs(t, err, &typeErr)
+
+	msg = codec.NewMessage("")
+	msg.Set("addr", map[string]any{"city": "Oslo", "zip": "0150"})
+	_, err = codec.DecodeInto[decodeOrder](msg)
+	assert.NoError(t, err)
+	_, err = codec.DecodeInto[decodeOrder](msg, codec.Strict)
+	assert.ErrorContains(t, err, `unknown field "zip"`)
+}
@@ -37,6 +37,8 @@ func (m *Message) GetBool(key string) bool {
 // toString tries to convert any value to a string.
 func toString(v any) (string, bool) {
 	switch x := v.(type) {
+	case nil:
+		return "", false
 	case string:
 		return x, true
 	case []byte:
@@ -67,3 +67,10 @@ func TestGetBool(t *testing.T) {
 	assert.Equal(t, false, msg.GetBool("str0"))
 	assert.Equal(t, false, msg.GetBool("bad"))
 }
+
+func TestGetString_Missing(t *testing.T) {
+	m := codec.NewMessage("")
+	assert.Equal(t, "", m.GetString("missing"))
+	m.Set("nil", nil)
+	assert.Equal(t, "", m.GetString("nil"))
+}
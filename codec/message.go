@@ -4,6 +4,7 @@ package codec
 import (
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 // Ensure Message implements IMessage.
@@ -34,12 +35,19 @@ type IMessage interface {
 	GetInt(key string) int64
 	GetFloat(key string) float64
 	GetBool(key string) bool
+	GetTime(key string) time.Time
+	SetTime(key string, t time.Time)
+	GetDuration(key string) time.Duration
+	SetDuration(key string, d time.Duration)
+	GetDecimal(key string) Decimal
+	SetDecimal(key string, d Decimal)
 
 	GetRawBody() []byte
 	UpdateRawBody() error
 
 	SetResult(value any)
 	GetResult(target any) error
+	BindBody(target any, mode ...DecodeMode) error
 	SetError(err error)
 	GetError() string
 	HasError() bool
@@ -150,23 +158,40 @@ func (m *Message) Get(key string) (any, bool) {
 	return v, ok
 }
 
+// SetBody replaces the body. Maps are deep-copied and raw JSON is decoded
+// directly; other values are converted through JSON. Values that cannot be
+// converted leave the body empty; use TrySetBody to see the error.
 func (m *Message) SetBody(obj any) {
-	if obj == nil {
-		m.Body, m.RawBody = nil, nil
-		return
+	_ = m.TrySetBody(obj)
+}
+
+// TrySetBody is SetBody returning the decode or conversion error.
+func (m *Message) TrySetBody(obj any) error {
+	m.Body, m.RawBody = nil, nil
+	switch x := obj.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		m.Body = cloneValue(x).(map[string]any)
+		return nil
+	case json.RawMessage:
+		var body map[string]any
+		if err := json.Unmarshal(x, &body); err != nil {
+			return err
+		}
+		m.Body = body
+		return nil
 	}
 	b, err := json.Marshal(obj)
 	if err != nil {
-		m.Body, m.RawBody = nil, nil
-		return
+		return err
 	}
 	var body map[string]any
 	if err := json.Unmarshal(b, &body); err != nil {
-		m.Body, m.RawBody = nil, nil
-		return
+		return err
 	}
 	m.Body = body
-	m.RawBody = nil
+	return nil
 }
 
 // Raw body
@@ -193,16 +218,18 @@ func (m *Message) SetResult(value any) {
 	m.Set("result", value)
 }
 
+// GetResult stores the "result" field in target. Values that already have
+// the target's type are copied directly; others are decoded from the
+// parsed value without re-encoding it.
 func (m *Message) GetResult(target any) error {
 	raw, ok := m.GetBodyMap()["result"]
 	if !ok {
 		return nil
 	}
-	b, err := json.Marshal(raw)
-	if err != nil {
-		return err
+	if assignValue(raw, target) {
+		return nil
 	}
-	return json.Unmarshal(b, target)
+	return decodeValue(raw, target)
 }
 
 func (m *Message) SetError(err error) {
@@ -210,6 +237,7 @@ func (m *Message) SetError(err error) {
 		m.Set("error", err.Error())
 	} else {
 		delete(m.GetBodyMap(), "error")
+		m.RawBody = nil
 	}
 }
 
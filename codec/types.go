@@ -0,0 +1,204 @@
+// file: mini/codec/types.go
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout is the canonical wire format for timestamps.
+const TimeLayout = time.RFC3339Nano
+
+var ErrInvalidDecimal = errors.New("codec: invalid decimal")
+
+// ----------------------------------------------------
+// Time and duration
+// ----------------------------------------------------
+
+// SetTime stores t as an RFC3339 string with nanoseconds and offset.
+func (m *Message) SetTime(key string, t time.Time) {
+	m.Set(key, t.Format(TimeLayout))
+}
+
+// GetTime returns the timestamp stored under key, or the zero time.
+func (m *Message) GetTime(key string) time.Time {
+	v, _ := m.Get(key)
+	t, _ := TimeValue(v)
+	return t
+}
+
+// SetDuration stores d in its canonical string form (e.g. "1m30s").
+func (m *Message) SetDuration(key string, d time.Duration) {
+	m.Set(key, d.String())
+}
+
+// GetDuration returns the duration stored under key, or zero.
+func (m *Message) GetDuration(key string) time.Duration {
+	v, _ := m.Get(key)
+	d, _ := DurationValue(v)
+	return d
+}
+
+// TimeValue converts a body value to time.Time. Strings must be RFC3339.
+func TimeValue(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		if x != nil {
+			return *x, true
+		}
+	case string:
+		t, err := time.Parse(TimeLayout, x)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// DurationValue converts a body value to time.Duration. Strings use
+// time.ParseDuration; numbers are nanoseconds, matching encoding/json.
+func DurationValue(v any) (time.Duration, bool) {
+	switch x := v.(type) {
+	case time.Duration:
+		return x, true
+	case string:
+		d, err := time.ParseDuration(x)
+		return d, err == nil
+	case int:
+		return time.Duration(x), true
+	case int64:
+		return time.Duration(x), true
+	case float64:
+		return time.Duration(x), true
+	}
+	return 0, false
+}
+
+// ----------------------------------------------------
+// Decimal
+// ----------------------------------------------------
+
+var decimalPattern = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+
+// Decimal is an exact decimal number carried as a string on the wire so it
+// never passes through float64. Scale is preserved ("1.50" stays "1.50").
+type Decimal string
+
+// maxDecimalExp bounds exponents so "1e999999999" cannot expand into a
+// huge string.
+const maxDecimalExp = 1000
+
+// ParseDecimal validates s and returns it in canonical form: plain
+// notation without exponent, no "+" sign or redundant leading zeros, and
+// the scale implied by the input ("+1.50" → "1.50", "1.5e-2" → "0.015",
+// "1.50e2" → "150", "-0" → "0").
+func ParseDecimal(s string) (Decimal, error) {
+	r, scale, err := parseDecimal(s)
+	if err != nil {
+		return "", err
+	}
+	return Decimal(r.FloatString(scale)), nil
+}
+
+// parseDecimal returns the exact value of s and its scale (fraction
+// digits after applying the exponent).
+func parseDecimal(s string) (*big.Rat, int, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return nil, 0, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	mant, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxDecimalExp || e < -maxDecimalExp {
+			return nil, 0, fmt.Errorf("%w: exponent out of range in %q", ErrInvalidDecimal, s)
+		}
+		mant, exp = s[:i], e
+	}
+	scale := 0
+	if _, frac, ok := strings.Cut(mant, "."); ok {
+		scale = len(frac)
+	}
+	scale = max(scale-exp, 0)
+
+	r, ok := new(big.Rat).SetString(strings.TrimSuffix(mant, "."))
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+	if exp != 0 {
+		pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil)
+		if exp > 0 {
+			r.Mul(r, new(big.Rat).SetInt(pow))
+		} else {
+			r.Quo(r, new(big.Rat).SetInt(pow))
+		}
+	}
+	return r, scale, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// DecimalFromRat formats r with the given number of fraction digits.
+func DecimalFromRat(r *big.Rat, scale int) Decimal {
+	return Decimal(r.FloatString(scale))
+}
+
+func (d Decimal) String() string { return string(d) }
+
+// IsZero reports whether d is empty or numerically zero.
+func (d Decimal) IsZero() bool {
+	r, ok := d.Rat()
+	return !ok || r.Sign() == 0
+}
+
+// Rat returns the exact value of d, or false if d is not a valid decimal.
+func (d Decimal) Rat() (*big.Rat, bool) {
+	r, _, err := parseDecimal(string(d))
+	return r, err == nil
+}
+
+// Float64 returns the nearest float64; use Rat for arithmetic.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(string(d), 64)
+	return f
+}
+
+// SetDecimal stores d as a string.
+func (m *Message) SetDecimal(key string, d Decimal) {
+	m.Set(key, string(d))
+}
+
+// GetDecimal returns the decimal stored under key, or "".
+func (m *Message) GetDecimal(key string) Decimal {
+	v, _ := m.Get(key)
+	d, _ := DecimalValue(v)
+	return d
+}
+
+// DecimalValue converts a body value to a canonical Decimal (see
+// ParseDecimal). Only strings and Decimal are accepted: JSON numbers decode
+// to float64 and may already have been rounded, so they are rejected rather
+// than passed off as exact.
+func DecimalValue(v any) (Decimal, bool) {
+	var s string
+	switch x := v.(type) {
+	case Decimal:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return "", false
+	}
+	d, err := ParseDecimal(s)
+	return d, err == nil
+}
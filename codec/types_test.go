@@ -0,0 +1,116 @@
+// file: mini/codec/types_test.go
+package codec_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime_RoundTrip(t *testing.T) {
+	loc := time.FixedZone("X", 3*3600)
+	ts := time.Date(2024, 5, 1, 10, 30, 0, 123456789, loc)
+
+	msg := codec.NewMessage("event")
+	msg.SetTime("at", ts)
+	assert.Equal(t, "2024-05-01T10:30:00.123456789+03:00", msg.GetString("at"))
+
+	data, err := codec.Marshal(msg)
+	assert.NoError(t, err)
+	out := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(data, out))
+	assert.True(t, ts.Equal(out.GetTime("at")))
+
+	msg.Set("bad", "yesterday")
+	assert.True(t, msg.GetTime("bad").IsZero())
+	assert.True(t, msg.GetTime("missing").IsZero())
+}
+
+func TestDuration_RoundTrip(t *testing.T) {
+	msg := codec.NewMessage("event")
+	msg.SetDuration("ttl", 90*time.Second)
+	assert.Equal(t, "1m30s", msg.GetString("ttl"))
+	assert.Equal(t, 90*time.Second, msg.GetDuration("ttl"))
+
+	// encoding/json encodes time.Duration as nanoseconds
+	msg.Set("raw", float64(time.Millisecond))
+	assert.Equal(t, time.Millisecond, msg.GetDuration("raw"))
+	assert.Equal(t, time.Duration(0), msg.GetDuration("missing"))
+}
+
+func TestDecimal_RoundTrip(t *testing.T) {
+	msg := codec.NewMessage("event")
+	big1, _ := codec.ParseDecimal("12345678901234567890.000000000000000001")
+	msg.SetDecimal("amount", big1)
+	msg.SetDecimal("price", "1.50")
+
+	data, err := codec.Marshal(msg)
+	assert.NoError(t, err)
+	out := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(data, out))
+
+	assert.Equal(t, big1, out.GetDecimal("amount"))
+	assert.Equal(t, codec.Decimal("1.50"), out.GetDecimal("price"))
+
+	r, ok := out.GetDecimal("amount").Rat()
+	assert.True(t, ok)
+	want, _ := new(big.Rat).SetString("12345678901234567890.000000000000000001")
+	assert.Equal(t, 0, r.Cmp(want))
+
+	msg.Set("f", 2.25)
+	assert.Equal(t, codec.Decimal(""), msg.GetDecimal("f"))
+	assert.Equal(t, codec.Decimal(""), msg.GetDecimal("missing"))
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, s := range []string{"0", "-1.5", "+3", ".5", "1e-3", "10."} {
+		_, err := codec.ParseDecimal(s)
+		assert.NoError(t, err, s)
+	}
+	for _, s := range []string{"", "1,5", "abc", "1/3", "--1"} {
+		_, err := codec.ParseDecimal(s)
+		assert.ErrorIs(t, err, codec.ErrInvalidDecimal, s)
+	}
+
+	d, _ := codec.ParseDecimal("+7.10")
+	assert.Equal(t, "7.10", d.String())
+	assert.InDelta(t, 7.1, d.Float64(), 1e-9)
+	assert.False(t, d.IsZero())
+	assert.True(t, codec.Decimal("0.00").IsZero())
+	assert.Equal(t, codec.Decimal("0.33"), codec.DecimalFromRat(big.NewRat(1, 3), 2))
+}
+
+func TestParseDecimal_Canonical(t *testing.T) {
+	for in, want := range map[string]string{
+		"+1.50":   "1.50",
+		"007.5":   "7.5",
+		".5":      "0.5",
+		"10.":     "10",
+		"-0":      "0",
+		"-0.00":   "0.00",
+		"1.5e-2":  "0.015",
+		"1.50e2":  "150",
+		"1.234E1": "12.34",
+		" 42 ":    "42",
+		"-2e+3":   "-2000",
+	} {
+		d, err := codec.ParseDecimal(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, d.String(), in)
+	}
+	for _, s := range []string{"1e1001", "1e-99999999999999999999", "0x10", "1_000", "NaN", "Inf"} {
+		_, err := codec.ParseDecimal(s)
+		assert.ErrorIs(t, err, codec.ErrInvalidDecimal, s)
+	}
+
+	d, ok := codec.DecimalValue(codec.Decimal("+1.0e1"))
+	assert.True(t, ok)
+	assert.Equal(t, codec.Decimal("10"), d)
+	_, ok = codec.DecimalValue(codec.Decimal("abc"))
+	assert.False(t, ok)
+	_, ok = codec.Decimal("1/3").Rat()
+	assert.False(t, ok)
+}
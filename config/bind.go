@@ -0,0 +1,163 @@
+// file: mini/config/bind.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ----------------------------------------------------
+// Typed binding
+// ----------------------------------------------------
+
+var ErrMissingKey = errors.New("config: missing key")
+
+// Validator is implemented by bound types that check their own values.
+type Validator interface {
+	Validate() error
+}
+
+// Bind decodes the value at key into target. Fields already set on target
+// act as defaults; unknown fields are rejected. If the type implements
+// Validator it is validated. target is left unchanged on error.
+//
+// Values may be nested JSON objects or JSON strings (e.g. from env vars).
+func Bind[T any](c IConfig, key string, target *T) error {
+	raw, ok := c.Get(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingKey, key)
+	}
+	val, err := decodeValue(raw, *target)
+	if err != nil {
+		return fmt.Errorf("config %s: %w", key, err)
+	}
+	*target = val
+	return nil
+}
+
+// decodeValue decodes raw over a deep copy of defaults and validates the
+// result.
+func decodeValue[T any](raw any, defaults T) (T, error) {
+	var data []byte
+	if s, ok := raw.(string); ok && looksLikeJSON(s) {
+		data = []byte(s)
+	} else {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return defaults, err
+		}
+		data = b
+	}
+
+	// Start from a deep copy so maps and slices in defaults are never
+	// written to or shared with the result.
+	var val T
+	base, err := json.Marshal(defaults)
+	if err != nil {
+		return defaults, err
+	}
+	if err := json.Unmarshal(base, &val); err != nil {
+		return defaults, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&val); err != nil {
+		return defaults, err
+	}
+	if v, ok := any(&val).(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return defaults, err
+		}
+	}
+	return val, nil
+}
+
+func looksLikeJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
+}
+
+// ----------------------------------------------------
+// Live binding (re-bound on reload)
+// ----------------------------------------------------
+
+// Binding holds a typed value that is re-bound after every reload that
+// changes its key. An invalid new value is rejected and the previous one kept.
+type Binding[T any] struct {
+	key      string
+	defaults T
+
+	mu        sync.RWMutex
+	value     T
+	err       error
+	listeners []func(T)
+}
+
+// Watch binds key like Bind and keeps it up to date across Reload calls.
+// A service's config is available as Options().Config.
+func Watch[T any](c IConfig, key string, defaults T) (*Binding[T], error) {
+	b := &Binding[T]{key: key, defaults: defaults, value: defaults}
+	if err := Bind(c, key, &b.value); err != nil {
+		return nil, err
+	}
+	c.OnChange(func(changes []Change) {
+		for _, ch := range changes {
+			if ch.Key == key {
+				b.rebind(ch.New)
+				return
+			}
+		}
+	})
+	return b, nil
+}
+
+// Get returns the current value.
+func (b *Binding[T]) Get() T {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.value
+}
+
+// Err returns the error of the last rejected update, if any.
+func (b *Binding[T]) Err() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.err
+}
+
+// OnUpdate registers fn to be called with each accepted new value.
+func (b *Binding[T]) OnUpdate(fn func(T)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners = append(b.listeners, fn)
+}
+
+func (b *Binding[T]) rebind(raw any) {
+	var (
+		val T
+		err error
+	)
+	if raw == nil {
+		err = fmt.Errorf("%w: %s", ErrMissingKey, b.key)
+	} else {
+		val, err = decodeValue(raw, b.defaults)
+	}
+
+	b.mu.Lock()
+	if err != nil {
+		b.err = fmt.Errorf("config %s: %w", b.key, err)
+		b.mu.Unlock()
+		return
+	}
+	b.value, b.err = val, nil
+	listeners := append([]func(T){}, b.listeners...)
+	b.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(val)
+	}
+}
@@ -0,0 +1,106 @@
+// file: mini/config/bind_test.go
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type dbConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	Pool int    `json:"pool"`
+}
+
+func (d *dbConfig) Validate() error {
+	if d.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	cfg, _ := config.New(config.WithDefaults(map[string]any{
+		"db":     map[string]any{"host": "localhost", "port": 5432},
+		"db_env": `{"host": "env", "port": 1}`,
+		"bad":    map[string]any{"host": "x", "port": 0},
+		"typo":   map[string]any{"hots": "x", "port": 1},
+	}))
+
+	db := dbConfig{Pool: 10}
+	assert.NoError(t, config.Bind(cfg, "db", &db))
+	assert.Equal(t, dbConfig{Host: "localhost", Port: 5432, Pool: 10}, db)
+
+	var env dbConfig
+	assert.NoError(t, config.Bind(cfg, "db_env", &env))
+	assert.Equal(t, "env", env.Host)
+
+	keep := dbConfig{Host: "keep", Port: 1}
+	assert.EqualError(t, config.Bind(cfg, "bad", &keep), "config bad: port must be positive")
+	assert.Equal(t, "keep", keep.Host)
+
+	assert.Error(t, config.Bind(cfg, "typo", &keep))
+	assert.ErrorIs(t, config.Bind(cfg, "missing", &keep), config.ErrMissingKey)
+}
+
+func TestWatch_RebindsOnReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"db": {"host": "a", "port": 1}}`), 0644))
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	b, err := config.Watch(cfg, "db", dbConfig{Pool: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "a", b.Get().Host)
+
+	var updates []dbConfig
+	b.OnUpdate(func(d dbConfig) { updates = append(updates, d) })
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"db": {"host": "b", "port": 2}}`), 0644))
+	_, err = cfg.Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, dbConfig{Host: "b", Port: 2, Pool: 5}, b.Get())
+	assert.Len(t, updates, 1)
+
+	// invalid update is rejected, previous value kept
+	assert.NoError(t, os.WriteFile(path, []byte(`{"db": {"host": "c", "port": 0}}`), 0644))
+	_, err = cfg.Reload()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", b.Get().Host)
+	assert.Error(t, b.Err())
+	assert.Len(t, updates, 1)
+}
+
+type poolConfig struct {
+	Hosts  []string       `json:"hosts"`
+	Limits map[string]int `json:"limits"`
+}
+
+func (p *poolConfig) Validate() error {
+	if len(p.Hosts) == 0 {
+		return errors.New("hosts required")
+	}
+	return nil
+}
+
+func TestBind_DefaultsNotShared(t *testing.T) {
+	cfg, _ := config.New(config.WithDefaults(map[string]any{
+		"ok":  map[string]any{"hosts": []any{"a"}, "limits": map[string]any{"read": 5}},
+		"bad": map[string]any{"hosts": []any{}, "limits": map[string]any{"write": 1}},
+	}))
+
+	target := poolConfig{Hosts: []string{"x"}, Limits: map[string]int{"conn": 1}}
+	assert.Error(t, config.Bind(cfg, "bad", &target))
+	assert.Equal(t, map[string]int{"conn": 1}, target.Limits)
+
+	defaults := poolConfig{Limits: map[string]int{"conn": 1}}
+	b, err := config.Watch(cfg, "ok", defaults)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"conn": 1, "read": 5}, b.Get().Limits)
+	assert.Equal(t, map[string]int{"conn": 1}, defaults.Limits)
+}
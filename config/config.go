@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 )
 
 // IConfig defines the interface for accessing and validating config.
@@ -15,16 +16,23 @@ type IConfig interface {
 	Dump(w io.Writer)
 	Get(key string) (any, bool)
 	MustString(key string) string
+	Profile() string
+	Reload() ([]Change, error)
+	OnChange(fn func([]Change))
 }
 
 // Config is the default implementation of IConfig.
 type Config struct {
-	values map[string]any
+	mu       sync.RWMutex
+	values   map[string]any
+	profile  string
+	opts     []Option
+	watchers []func([]Change)
 }
 
 // New creates a new config from default, file or environment.
 func New(opts ...Option) (*Config, error) {
-	cfg := &Config{values: make(map[string]any)}
+	cfg := &Config{values: make(map[string]any), opts: opts}
 	for _, o := range opts {
 		if err := o(cfg); err != nil {
 			return nil, err
@@ -35,6 +43,9 @@ func New(opts ...Option) (*Config, error) {
 
 // Validate required fields.
 func (c *Config) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	required := []string{"service_name", "bus_addr", "log_level", "port"}
 	var missing []string
 	for _, key := range required {
@@ -50,18 +61,24 @@ func (c *Config) Validate() error {
 
 // String returns pretty-printed JSON.
 func (c *Config) String() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	data, _ := json.MarshalIndent(c.values, "", "  ")
 	return string(data)
 }
 
 // Dump writes JSON config to writer.
 func (c *Config) Dump(w io.Writer) {
+	c.mu.RLock()
 	data, _ := json.MarshalIndent(c.values, "", "  ")
+	c.mu.RUnlock()
 	_, _ = w.Write(data)
 }
 
 // Get returns a value from config.
 func (c *Config) Get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	v, ok := c.values[key]
 	return v, ok
 }
@@ -72,3 +89,10 @@ func (c *Config) MustString(key string) string {
 	s, _ := v.(string)
 	return s
 }
+
+// Profile returns the active overlay profile, or "" if none was applied.
+func (c *Config) Profile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.profile
+}
@@ -2,8 +2,6 @@
 package config
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -25,15 +23,9 @@ func WithDefaults(defaults map[string]any) Option {
 // FromJSON loads config from a JSON file.
 func FromJSON(path string) Option {
 	return func(c *Config) error {
-		data, err := os.ReadFile(path)
+		raw, err := readJSONFile(path)
 		if err != nil {
-			return fmt.Errorf("read config file: %w", err)
-		}
-		data = ReplaceEnvVars(data)
-
-		var raw map[string]any
-		if err := json.Unmarshal(data, &raw); err != nil {
-			return fmt.Errorf("parse config json: %w", err)
+			return err
 		}
 		for k, v := range raw {
 			c.values[strings.ToLower(k)] = v
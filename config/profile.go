@@ -0,0 +1,105 @@
+// file: mini/config/profile.go
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ----------------------------------------------------
+// Profile overlays
+// ----------------------------------------------------
+
+const (
+	// DefaultProfileEnv selects the active profile (e.g. "production").
+	DefaultProfileEnv = "SRV_PROFILE"
+	// BaseProfileFile is the shared config every profile builds on.
+	BaseProfileFile = "base.json"
+)
+
+// FromProfile loads dir/base.json and deep-merges dir/<profile>.json on top.
+// The profile name is read from envVar (DefaultProfileEnv if empty).
+// Precedence follows option order: values loaded here override earlier
+// options and are overridden by later ones (e.g. FromEnv).
+func FromProfile(dir, envVar string) Option {
+	if envVar == "" {
+		envVar = DefaultProfileEnv
+	}
+	return func(c *Config) error {
+		base, err := readJSONFile(filepath.Join(dir, BaseProfileFile))
+		if err != nil {
+			return err
+		}
+		merged := lowerKeys(base)
+
+		profile := strings.TrimSpace(os.Getenv(envVar))
+		if profile != "" {
+			overlay, err := readJSONFile(filepath.Join(dir, profile+".json"))
+			if err != nil {
+				return fmt.Errorf("profile %q: %w", profile, err)
+			}
+			merged = DeepMerge(merged, lowerKeys(overlay))
+		}
+
+		for key, v := range merged {
+			if dst, ok := c.values[key].(map[string]any); ok {
+				if src, ok := v.(map[string]any); ok {
+					v = DeepMerge(dst, src)
+				}
+			}
+			c.values[key] = v
+		}
+		c.profile = profile
+		return nil
+	}
+}
+
+// DeepMerge returns a new map with overlay merged into base.
+// Nested objects are merged recursively; any other value in overlay replaces base.
+func DeepMerge(base, overlay map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range overlay {
+		if src, ok := v.(map[string]any); ok {
+			if dst, ok := out[k].(map[string]any); ok {
+				out[k] = DeepMerge(dst, src)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// lowerKeys returns a copy of m with keys lowercased at every depth, so
+// "Log_Level" in one file and "log_level" in another name the same key.
+func lowerKeys(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			v = lowerKeys(sub)
+		}
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// readJSONFile reads a JSON object with ${ENV_VAR} interpolation.
+func readJSONFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	data = ReplaceEnvVars(data)
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config json: %w", err)
+	}
+	return raw, nil
+}
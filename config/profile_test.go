@@ -0,0 +1,93 @@
+// file: mini/config/profile_test.go
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProfileFiles(t *testing.T) string {
+	dir := t.TempDir()
+	base := `{"Log_Level": "info", "port": "8080", "db": {"host": "localhost", "pool": 5}}`
+	prod := `{"log_level": "warn", "db": {"host": "db.prod"}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "base.json"), []byte(base), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "production.json"), []byte(prod), 0644))
+	return dir
+}
+
+func TestFromProfile_BaseOnly(t *testing.T) {
+	dir := writeProfileFiles(t)
+	t.Setenv("TEST_PROFILE", "")
+
+	cfg, err := config.New(config.FromProfile(dir, "TEST_PROFILE"))
+	assert.NoError(t, err)
+	assert.Equal(t, "", cfg.Profile())
+	assert.Equal(t, "info", cfg.MustString("log_level"))
+}
+
+func TestFromProfile_Overlay(t *testing.T) {
+	dir := writeProfileFiles(t)
+	t.Setenv("TEST_PROFILE", "production")
+
+	cfg, err := config.New(config.FromProfile(dir, "TEST_PROFILE"))
+	assert.NoError(t, err)
+	assert.Equal(t, "production", cfg.Profile())
+	assert.Equal(t, "warn", cfg.MustString("log_level"))
+	assert.Equal(t, "8080", cfg.MustString("port"))
+
+	db, _ := cfg.Get("db")
+	assert.Equal(t, map[string]any{"host": "db.prod", "pool": float64(5)}, db)
+}
+
+func TestFromProfile_MissingOverlay(t *testing.T) {
+	dir := writeProfileFiles(t)
+	t.Setenv("TEST_PROFILE", "staging")
+
+	cfg, err := config.New(config.FromProfile(dir, "TEST_PROFILE"))
+	assert.Nil(t, cfg)
+	assert.Error(t, err)
+}
+
+func TestFromProfile_Precedence(t *testing.T) {
+	dir := writeProfileFiles(t)
+	t.Setenv("TEST_PROFILE", "production")
+	t.Setenv("SRV_PREC_LOG_LEVEL", "debug")
+
+	cfg, err := config.New(
+		config.WithDefaults(map[string]any{"log_level": "error", "extra": "x"}),
+		config.FromProfile(dir, "TEST_PROFILE"),
+		config.FromEnv("SRV_PREC_"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", cfg.MustString("log_level"))
+	assert.Equal(t, "x", cfg.MustString("extra"))
+}
+
+func TestDeepMerge(t *testing.T) {
+	base := map[string]any{"a": 1, "n": map[string]any{"x": 1, "y": 2}}
+	over := map[string]any{"b": 2, "n": map[string]any{"y": 3}}
+
+	out := config.DeepMerge(base, over)
+	assert.Equal(t, 1, out["a"])
+	assert.Equal(t, 2, out["b"])
+	assert.Equal(t, map[string]any{"x": 1, "y": 3}, out["n"])
+	assert.Equal(t, map[string]any{"x": 1, "y": 2}, base["n"])
+}
+
+func TestFromProfile_MixedCaseNested(t *testing.T) {
+	dir := t.TempDir()
+	base := `{"DB": {"Host": "localhost", "Pool": 5}}`
+	prod := `{"db": {"host": "db.prod"}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "base.json"), []byte(base), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "production.json"), []byte(prod), 0644))
+	t.Setenv("TEST_PROFILE", "production")
+
+	cfg, err := config.New(config.FromProfile(dir, "TEST_PROFILE"))
+	assert.NoError(t, err)
+	db, _ := cfg.Get("db")
+	assert.Equal(t, map[string]any{"host": "db.prod", "pool": float64(5)}, db)
+}
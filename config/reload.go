@@ -0,0 +1,76 @@
+// file: mini/config/reload.go
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ----------------------------------------------------
+// Reload and diff
+// ----------------------------------------------------
+
+// Change describes a single config key that differs after a reload.
+// Old is nil for added keys, New is nil for removed keys.
+type Change struct {
+	Key string `json:"key"`
+	Old any    `json:"old,omitempty"`
+	New any    `json:"new,omitempty"`
+}
+
+// Reload re-applies the options the config was created with (re-reading
+// files and env), swaps in the new values and returns what changed.
+// On error the current values are kept.
+func (c *Config) Reload() ([]Change, error) {
+	c.mu.RLock()
+	opts := c.opts
+	c.mu.RUnlock()
+
+	fresh, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	changes := Diff(c.values, fresh.values)
+	c.values = fresh.values
+	c.profile = fresh.profile
+	watchers := append([]func([]Change){}, c.watchers...)
+	c.mu.Unlock()
+
+	if len(changes) > 0 {
+		for _, fn := range watchers {
+			fn(changes)
+		}
+	}
+	return changes, nil
+}
+
+// OnChange registers fn to be called after a reload that changed values.
+func (c *Config) OnChange(fn func([]Change)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchers = append(c.watchers, fn)
+}
+
+// Diff returns the changes from old to new, sorted by key.
+func Diff(old, new map[string]any) []Change {
+	var changes []Change
+	for k, ov := range old {
+		nv, ok := new[k]
+		if !ok {
+			changes = append(changes, Change{Key: k, Old: ov})
+			continue
+		}
+		if !reflect.DeepEqual(ov, nv) {
+			changes = append(changes, Change{Key: k, Old: ov, New: nv})
+		}
+	}
+	for k, nv := range new {
+		if _, ok := old[k]; !ok {
+			changes = append(changes, Change{Key: k, New: nv})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
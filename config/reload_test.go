@@ -0,0 +1,48 @@
+// file: mini/config/reload_test.go
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_level": "info", "port": "80", "old": 1}`), 0644))
+
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_level": "debug", "port": "80", "new": true}`), 0644))
+	changes, err := cfg.Reload()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []config.Change{
+		{Key: "log_level", Old: "info", New: "debug"},
+		{Key: "new", New: true},
+		{Key: "old", Old: float64(1)},
+	}, changes)
+	assert.Equal(t, "debug", cfg.MustString("log_level"))
+}
+
+func TestConfig_ReloadErrorKeepsValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_level": "info"}`), 0644))
+
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{bad`), 0644))
+	_, err = cfg.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "info", cfg.MustString("log_level"))
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	m := map[string]any{"a": 1, "b": map[string]any{"c": "d"}}
+	assert.Empty(t, config.Diff(m, map[string]any{"a": 1, "b": map[string]any{"c": "d"}}))
+}
@@ -68,6 +68,20 @@ const (
 	BodyKeyInput  = "input"
 )
 
+// ----------------------------------------------------
+// Header keys (standardized)
+// ----------------------------------------------------
+
+const (
+	HeaderCorrelationID = "correlation_id"
+	HeaderCausationID   = "causation_id"
+
+	// W3C Trace Context and Baggage
+	HeaderTraceparent = "traceparent"
+	HeaderTracestate  = "tracestate"
+	HeaderBaggage     = "baggage"
+)
+
 // ----------------------------------------------------
 // Action / invoke keys
 // ----------------------------------------------------
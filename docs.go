@@ -0,0 +1,297 @@
+// file: mini/docs.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/transport"
+)
+
+// ----------------------------------------------------
+// Docs and static assets over HTTP
+// ----------------------------------------------------
+
+// OpenAPIDocument builds an OpenAPI 3 document with one POST path per action.
+func (s *Service) OpenAPIDocument() map[string]any {
+	schemas := s.GetOpenAPISchemas()
+	paths := make(map[string]any, len(schemas))
+	for name := range schemas {
+		paths["/"+name] = map[string]any{
+			"post": map[string]any{
+				"operationId": name,
+				"requestBody": map[string]any{
+					"content": map[string]any{
+						"application/json": map[string]any{
+							"schema": map[string]any{"$ref": "#/components/schemas/" + name},
+						},
+					},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{"description": "OK"},
+					"400": map[string]any{"description": "Validation failed"},
+					"404": map[string]any{"description": "Unknown action or object"},
+					"500": map[string]any{"description": "Action error"},
+				},
+			},
+		}
+	}
+	doc := map[string]any{
+		"openapi":    "3.0.3",
+		"info":       map[string]any{"title": s.name, "version": s.version},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+	if s.opts.HTTPActions {
+		doc["servers"] = []any{map[string]any{"url": actionsPrefix}}
+	}
+	return doc
+}
+
+// DescribeAction returns the validation schema of one action.
+const DescribeAction = "router.describe"
+
+// describeAction handles DescribeAction with input {"id": "<node>"} and
+// returns the JSON Schema the router validates that node with.
+func (s *Service) describeAction(_ context.Context, input map[string]any) (any, error) {
+	id, _ := input["id"].(string)
+	return s.opts.Router.Describe(id)
+}
+
+// registerDocs adds docs and static routes to mux.
+func (s *Service) registerDocs(mux *http.ServeMux) {
+	if s.opts.HTTPDocs {
+		mux.Handle("/openapi.json", s.httpAuth(http.HandlerFunc(s.serveOpenAPI)))
+		mux.Handle("/docs", s.httpAuth(http.HandlerFunc(s.serveDocs)))
+		mux.Handle("/docs/services", s.httpAuth(http.HandlerFunc(s.serveServices)))
+	}
+	if s.opts.HTTPActions {
+		mux.Handle(actionsPrefix+"/", s.httpAuth(http.HandlerFunc(s.serveAction)))
+	}
+	if s.opts.HTTPStaticDir != "" {
+		prefix := "/" + strings.Trim(s.opts.HTTPStaticPrefix, "/") + "/"
+		if prefix == "//" {
+			prefix = "/static/"
+		}
+		fs := http.StripPrefix(prefix, http.FileServer(http.Dir(s.opts.HTTPStaticDir)))
+		mux.Handle(prefix, s.httpAuth(fs))
+	}
+}
+
+// httpAuth requires "Authorization: Bearer <token>" when HTTPAuthToken is set.
+// Probes are never wrapped so orchestrators can reach them.
+func (s *Service) httpAuth(next http.Handler) http.Handler {
+	token := s.opts.HTTPAuthToken
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Service) serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.OpenAPIDocument())
+}
+
+func (s *Service) serveServices(w http.ResponseWriter, _ *http.Request) {
+	services, err := s.opts.Registry.ListServices()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"services": services})
+}
+
+// ----------------------------------------------------
+// Action bridge
+// ----------------------------------------------------
+
+const (
+	actionsPrefix     = "/actions"
+	maxActionBodySize = 1 << 20
+)
+
+// serveAction runs POST /actions/<name> in-process: the JSON body is the
+// input, validated and passed through the service middlewares like a bus
+// request. It answers {"result": ...} or {"error": ..., "fields": ...}.
+func (s *Service) serveAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"error": "method not allowed"})
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, actionsPrefix+"/")
+	info, ok := s.actions[name]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": constant.ErrNotFound.Error()})
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxActionBodySize))
+	if err != nil {
+		code := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		writeJSON(w, code, map[string]any{"error": err.Error()})
+		return
+	}
+	input := map[string]any{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &input); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid JSON body: " + err.Error()})
+			return
+		}
+	}
+	if input == nil {
+		input = map[string]any{}
+	}
+
+	ctxID := uuid.NewString()
+	ctx := context.WithValue(r.Context(), ContextIDKey, ctxID)
+	ctx = context.WithValue(ctx, ActionNameKey, name)
+	ctx = context.WithValue(ctx, PayloadSizeKey, len(raw))
+	if tp, err := transport.ParseTraceparent(r.Header.Get(constant.HeaderTraceparent)); err == nil {
+		ctx = transport.WithTraceParent(ctx, tp)
+		ctx = context.WithValue(ctx, TraceIDKey, tp.TraceID)
+	}
+
+	result, err := s.runAction(ctx, info, input)
+	if err != nil {
+		s.logger.WithContext(ctxID).Warn("http action %s: %v", name, err)
+		body := map[string]any{"error": err.Error()}
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			body["fields"] = verr.Fields
+		}
+		writeJSON(w, errorStatus(err), body)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"result": result})
+}
+
+// runAction validates input and calls the action through the middlewares,
+// turning a panic into an error.
+func (s *Service) runAction(ctx context.Context, info actionInfo, input map[string]any) (result any, err error) {
+	if len(info.schema) > 0 {
+		if err := validateInput(info.schema, input); err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("panic in action: %v", r)
+			result, err = nil, errors.New("internal error")
+		}
+	}()
+	ctx = withInput(ctx, info.schema, input)
+	return chainMiddlewares(info.handler, s.middlewares...)(ctx, input)
+}
+
+// ----------------------------------------------------
+// Docs page
+// ----------------------------------------------------
+
+type docsAction struct {
+	Name    string
+	Fields  []InputSchemaField
+	Example string
+}
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Name}} {{.Version}}</title>
+<style>body{font-family:sans-serif;margin:2em}table{border-collapse:collapse}td,th{border:1px solid #ccc;padding:4px 8px}
+textarea{width:40em;height:6em;font-family:monospace}pre{background:#f4f4f4;padding:8px}</style>
+</head><body>
+<h1>{{.Name}} <small>{{.Version}}</small></h1>
+<p><a href="openapi.json">openapi.json</a> · <a href="docs/services">services</a></p>
+{{if .TryIt}}<p><label>Bearer token <input id="token" type="password"></label></p>{{end}}
+{{range .Actions}}<h2>{{.Name}}</h2>
+{{if .Fields}}<table><tr><th>field</th><th>type</th><th>required</th><th>default</th></tr>
+{{range .Fields}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Required}}</td><td>{{if .Default}}{{.Default}}{{end}}</td></tr>
+{{end}}</table>{{else}}<p>No input.</p>{{end}}
+{{if $.TryIt}}<form class="try" data-action="{{.Name}}"><textarea name="body">{{.Example}}</textarea><br>
+<button type="submit">Send</button></form><pre class="out" hidden></pre>{{end}}
+{{end}}
+{{if .TryIt}}<script>
+document.querySelectorAll("form.try").forEach(function (form) {
+  form.addEventListener("submit", function (e) {
+    e.preventDefault();
+    var out = form.nextElementSibling;
+    var headers = {"Content-Type": "application/json"};
+    var token = document.getElementById("token").value;
+    if (token) headers["Authorization"] = "Bearer " + token;
+    fetch("{{.ActionsURL}}/" + encodeURIComponent(form.dataset.action), {
+      method: "POST", headers: headers, body: form.elements.body.value
+    }).then(function (r) {
+      return r.text().then(function (t) { out.textContent = r.status + " " + r.statusText + "\n" + t; });
+    }).catch(function (err) { out.textContent = String(err); })
+      .finally(function () { out.hidden = false; });
+  });
+});
+</script>{{end}}
+</body></html>
+`))
+
+// serveDocs renders a browsable page of actions and their input schemas,
+// with a try-it form per action when the action bridge is enabled.
+func (s *Service) serveDocs(w http.ResponseWriter, _ *http.Request) {
+	names := s.ListActions()
+	sort.Strings(names)
+	actions := make([]docsAction, 0, len(names))
+	for _, name := range names {
+		schema, _ := s.ActionSchema(name)
+		actions = append(actions, docsAction{Name: name, Fields: schema, Example: exampleInput(schema)})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = docsTemplate.Execute(w, map[string]any{
+		"Name":       s.name,
+		"Version":    s.version,
+		"Actions":    actions,
+		"TryIt":      s.opts.HTTPActions,
+		"ActionsURL": actionsPrefix,
+	})
+}
+
+// exampleInput returns an indented JSON body with each field set to its
+// default or a placeholder for its type.
+func exampleInput(schema []InputSchemaField) string {
+	body := make(map[string]any, len(schema))
+	for _, f := range schema {
+		switch {
+		case f.Default != nil:
+			body[f.Name] = f.Default
+		case f.Type == TypeInt || f.Type == TypeNumber:
+			body[f.Name] = 0
+		case f.Type == TypeBool:
+			body[f.Name] = false
+		case f.Type == TypeObject:
+			body[f.Name] = map[string]any{}
+		case f.Type == TypeArray:
+			body[f.Name] = []any{}
+		default:
+			body[f.Name] = ""
+		}
+	}
+	data, _ := json.MarshalIndent(body, "", "  ")
+	return string(data)
+}
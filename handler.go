@@ -10,6 +10,7 @@ import (
 	"github.com/rskv-p/mini/context"
 	"github.com/rskv-p/mini/recover"
 	"github.com/rskv-p/mini/router"
+	"github.com/rskv-p/mini/transport"
 )
 
 // ----------------------------------------------------
@@ -18,15 +19,21 @@ import (
 
 // ServerHandler processes incoming transport messages.
 func (s *Service) ServerHandler(msg codec.IMessage) {
+	s.serveMessage(dcont.Background(), msg)
+}
+
+// serveMessage is ServerHandler with a base context for the handlers,
+// e.g. carrying the wire payload size (see PayloadSizeFrom).
+func (s *Service) serveMessage(base dcont.Context, msg codec.IMessage) {
 	defer recover.RecoverWithContext(s.name, "ServerHandler", msg)
 
 	switch msg.GetType() {
 	case constant.MessageTypeRequest:
-		s.handleRequest(msg, msg.GetReplyTo())
+		s.handleRequest(base, msg, msg.GetReplyTo())
 	case constant.MessageTypeResponse:
 		s.handleResponse(msg, nil)
 	case constant.MessageTypePublish:
-		s.handlePublish(msg)
+		s.handlePublish(base, msg)
 	case constant.MessageTypeHealthCheck:
 		s.handleHealthCheck(msg, msg.GetReplyTo())
 	default:
@@ -39,7 +46,7 @@ func (s *Service) ServerHandler(msg codec.IMessage) {
 // ----------------------------------------------------
 
 // handleRequest routes and executes a service request.
-func (s *Service) handleRequest(msg codec.IMessage, replyTo string) {
+func (s *Service) handleRequest(base dcont.Context, msg codec.IMessage, replyTo string) {
 	defer recover.RecoverWithContext(s.name, "handleRequest", msg)
 
 	if replyTo != "" {
@@ -65,7 +72,7 @@ func (s *Service) handleRequest(msg codec.IMessage, replyTo string) {
 	go func() {
 		defer recover.RecoverWithContext(s.name, "handleRequest.Inner", msg)
 
-		ctx := s.messageContext(msg)
+		ctx := s.messageContext(base, msg)
 		handler = router.Wrap(handler, s.opts.HdlrWrappers)
 
 		if herr := handler(ctx, msg, replyTo); herr != nil {
@@ -112,7 +119,7 @@ func (s *Service) handleResponse(msg codec.IMessage, raw []byte) {
 }
 
 // handlePublish routes a publish message without reply.
-func (s *Service) handlePublish(msg codec.IMessage) {
+func (s *Service) handlePublish(base dcont.Context, msg codec.IMessage) {
 	defer recover.RecoverWithContext(s.name, "handlePublish", msg)
 
 	handler, err := s.opts.Router.Dispatch(msg)
@@ -125,7 +132,7 @@ func (s *Service) handlePublish(msg codec.IMessage) {
 	go func() {
 		defer recover.RecoverWithContext(s.name, "handlePublish.Inner", msg)
 
-		ctx := s.messageContext(msg)
+		ctx := s.messageContext(base, msg)
 		handler = router.Wrap(handler, s.opts.HdlrWrappers)
 		_ = handler(ctx, msg, "")
 		s.IncMetric("publish_handled")
@@ -153,7 +160,9 @@ func (s *Service) handleHealthCheck(msg codec.IMessage, replyTo string) {
 // Context utils
 // ----------------------------------------------------
 
-// messageContext builds context.Context from message metadata.
-func (s *Service) messageContext(msg codec.IMessage) dcont.Context {
-	return dcont.WithValue(dcont.Background(), ContextIDKey, msg.GetContextID())
+// messageContext builds context.Context on base from message metadata.
+// Downstream calls made with this context inherit the message's correlation.
+func (s *Service) messageContext(base dcont.Context, msg codec.IMessage) dcont.Context {
+	ctx := dcont.WithValue(base, ContextIDKey, msg.GetContextID())
+	return transport.ContextFromMessage(ctx, msg)
 }
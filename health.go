@@ -80,6 +80,15 @@ func healthCheck(cfg config.IConfig) (int, map[string]any) {
 	return status, feedback
 }
 
+// probeHealth evaluates only the registered probes, which report the
+// health of the modules a service is built from.
+func probeHealth() (int, map[string]any) {
+	status := constant.StatusOK
+	feedback := make(map[string]any)
+	checkCustomProbes(&status, feedback)
+	return status, feedback
+}
+
 // ----------------------------------------------------
 // Internal checkers
 // ----------------------------------------------------
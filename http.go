@@ -0,0 +1,175 @@
+// file: mini/http.go
+package service
+
+import (
+	dcont "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rskv-p/mini/constant"
+)
+
+// ----------------------------------------------------
+// Embedded HTTP probes (/healthz, /readyz, /metrics)
+// ----------------------------------------------------
+
+const httpShutdownTimeout = 5 * time.Second
+
+// httpAddr resolves the listen address from options or the "port" config
+// key, which may be a string ("8080", ":8080") or a number (FromEnv, JSON).
+func (s *Service) httpAddr() string {
+	if s.opts.HTTPAddr != "" {
+		return s.opts.HTTPAddr
+	}
+	var port string
+	switch v, _ := s.config.Get("port"); x := v.(type) {
+	case string:
+		port = strings.TrimSpace(x)
+	case int:
+		port = strconv.Itoa(x)
+	case int64:
+		port = strconv.FormatInt(x, 10)
+	case float64:
+		port = strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	if port == "" {
+		return ""
+	}
+	if strings.Contains(port, ":") {
+		return port
+	}
+	return ":" + port
+}
+
+// httpHandler builds the probe mux.
+func (s *Service) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.serveHealthz)
+	mux.HandleFunc("/readyz", s.serveReadyz)
+	mux.HandleFunc("/metrics", s.serveMetrics)
+	s.registerDocs(mux)
+	return mux
+}
+
+// startHTTP starts the probe listener if enabled.
+func (s *Service) startHTTP() error {
+	if !s.opts.HTTP {
+		return nil
+	}
+	addr := s.httpAddr()
+	if addr == "" {
+		return errors.New("http: no listen address (set HTTPAddr or config port)")
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("http listen %s: %w", addr, err)
+	}
+
+	srv := &http.Server{Handler: s.httpHandler(), ReadHeaderTimeout: 5 * time.Second}
+	s.mu.Lock()
+	s.httpSrv = srv
+	s.mu.Unlock()
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("http server error: %v", err)
+		}
+	}()
+	s.logger.Info("http probes listening on %s", ln.Addr())
+	return nil
+}
+
+// stopHTTP gracefully shuts down the probe listener.
+func (s *Service) stopHTTP() error {
+	s.mu.Lock()
+	srv := s.httpSrv
+	s.httpSrv = nil
+	s.mu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	ctx, cancel := dcont.WithTimeout(dcont.Background(), httpShutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// ----------------------------------------------------
+// Handlers
+// ----------------------------------------------------
+
+func (s *Service) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	status, checks := healthCheck(s.config)
+
+	code := http.StatusOK
+	if status >= constant.StatusCritical {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{
+		"service": s.name,
+		"status":  status,
+		"checks":  checks,
+	})
+}
+
+func (s *Service) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	connected := s.opts.Transport != nil && s.opts.Transport.IsConnected()
+	registered := s.registered.Load()
+	modStatus, modules := probeHealth()
+
+	code := http.StatusOK
+	if !connected || !registered || modStatus >= constant.StatusCritical {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{
+		"service":    s.name,
+		"transport":  connected,
+		"registered": registered,
+		"modules":    modules,
+	})
+}
+
+// serveMetrics writes metrics in Prometheus text exposition format.
+func (s *Service) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	metrics := s.ExportMetrics()
+	names := make([]string, 0, len(metrics))
+	for k := range metrics {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s %v\n", metricName(name), metrics[name])
+	}
+}
+
+// ----------------------------------------------------
+// Helpers
+// ----------------------------------------------------
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// metricName converts a dotted metric name into a Prometheus-safe one.
+func metricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,269 @@
+// file: mini/http_test.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/registry"
+	"github.com/rskv-p/mini/router"
+	"github.com/rskv-p/mini/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHTTPTestService(t *testing.T, values map[string]any) *Service {
+	cfg, err := config.New(config.WithDefaults(values))
+	assert.NoError(t, err)
+	return &Service{
+		name:    "probe",
+		config:  cfg,
+		logger:  &testLogger{},
+		metrics: make(map[string]int64),
+		opts:    Options{Transport: transport.New()},
+	}
+}
+
+func TestHTTP_Healthz(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	rec := httptest.NewRecorder()
+	s.httpHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "probe", body["service"])
+}
+
+func TestHTTP_ReadyzNotReady(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	s.registered.Store(true)
+
+	rec := httptest.NewRecorder()
+	s.httpHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, false, body["transport"])
+	assert.Equal(t, true, body["registered"])
+}
+
+func TestHTTP_ReadyzModules(t *testing.T) {
+	healthProbesMu.RLock()
+	saved := healthProbes
+	healthProbesMu.RUnlock()
+	t.Cleanup(func() {
+		healthProbesMu.Lock()
+		healthProbes = saved
+		healthProbesMu.Unlock()
+	})
+
+	var failing atomic.Bool
+	RegisterHealthProbe(func() (string, int, any) {
+		if !failing.Load() {
+			return "", constant.StatusOK, nil
+		}
+		return "db", constant.StatusCritical, "connection refused"
+	})
+
+	s := newHTTPTestService(t, nil)
+	s.registered.Store(true)
+	failing.Store(true)
+
+	rec := httptest.NewRecorder()
+	s.httpHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{"db": "connection refused"}, body["modules"])
+}
+
+func TestHTTP_StartUnwindsOnListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	tr := &stubTransport{}
+	reg := registry.NewRegistry()
+	s := newHTTPTestService(t, nil)
+	s.opts.Transport = tr
+	s.opts.Registry = reg
+	s.opts.HTTP = true
+	s.opts.HTTPAddr = ln.Addr().String()
+
+	assert.Error(t, s.start())
+	assert.False(t, tr.subscribed)
+	assert.False(t, s.registered.Load())
+	assert.Equal(t, 0, reg.TotalNodes("probe"))
+}
+
+func TestHTTP_Metrics(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	s.IncMetric("errors_total")
+	s.WithMetricPrefix("db").Add("queries", 3)
+
+	rec := httptest.NewRecorder()
+	s.httpHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "db_queries 3\nerrors_total 1\n", rec.Body.String())
+}
+
+func TestHTTP_Addr(t *testing.T) {
+	t.Setenv("SRV_HTTPADDR_PORT", "9090")
+	cfg, err := config.New(config.FromEnv("SRV_HTTPADDR_"))
+	assert.NoError(t, err)
+	s := newHTTPTestService(t, nil)
+	s.config = cfg
+	assert.Equal(t, ":9090", s.httpAddr())
+
+	s = newHTTPTestService(t, map[string]any{"port": "127.0.0.1:9091"})
+	assert.Equal(t, "127.0.0.1:9091", s.httpAddr())
+
+	s.opts.HTTPAddr = "127.0.0.1:7070"
+	assert.Equal(t, "127.0.0.1:7070", s.httpAddr())
+}
+
+func TestHTTP_StartStop(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	assert.NoError(t, s.startHTTP()) // disabled: no-op
+
+	s.opts.HTTP = true
+	s.opts.HTTPAddr = "127.0.0.1:0"
+	assert.NoError(t, s.startHTTP())
+	assert.NotNil(t, s.httpSrv)
+	assert.NoError(t, s.stopHTTP())
+	assert.Nil(t, s.httpSrv)
+}
+
+func TestHTTP_Docs(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	s.version = "1.0.0"
+	s.opts.HTTPDocs = true
+	s.opts.Registry = registry.NewRegistry()
+	s.RegisterAction("user.get", []InputSchemaField{{Name: "id", Type: TypeString, Required: true}}, nil)
+	h := s.httpHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/user.get")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "user.get")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/services", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHTTP_DocsAuthAndStatic(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("ok"), 0644))
+
+	s := newHTTPTestService(t, nil)
+	s.opts.HTTPDocs = true
+	s.opts.HTTPStaticDir = dir
+	s.opts.HTTPStaticPrefix = "ui"
+	s.opts.HTTPAuthToken = "secret"
+	h := s.httpHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/ui/app.js", nil)
+	req.Header.Set("Authorization", "secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/ui/app.js", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHTTP_Actions(t *testing.T) {
+	s := newHTTPTestService(t, nil)
+	s.opts.HTTPDocs = true
+	s.opts.HTTPActions = true
+	s.opts.HTTPAuthToken = "secret"
+	s.RegisterAction("math.double", []InputSchemaField{{Name: "n", Type: TypeInt, Required: true}},
+		func(_ context.Context, input map[string]any) (any, error) {
+			n := input["n"].(float64)
+			if n < 0 {
+				return nil, &router.Error{StatusCode: 422, Message: "negative"}
+			}
+			if n == 13 {
+				panic("unlucky")
+			}
+			return n * 2, nil
+		})
+	h := s.httpHandler()
+
+	call := func(method, path, body string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec, out
+	}
+
+	rec, out := call(http.MethodPost, "/actions/math.double", `{"n":21}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 42.0, out["result"])
+
+	rec, out = call(http.MethodPost, "/actions/math.double", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, out, "fields")
+
+	rec, _ = call(http.MethodPost, "/actions/math.double", `{"n":-1}`)
+	assert.Equal(t, 422, rec.Code)
+
+	rec, out = call(http.MethodPost, "/actions/math.double", `{"n":13}`)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal error", out["error"])
+
+	rec, _ = call(http.MethodPost, "/actions/missing", `{}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec, _ = call(http.MethodGet, "/actions/math.double", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/actions/math.double", strings.NewReader(`{"n":1}`)))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = call(http.MethodGet, "/docs", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `data-action="math.double"`)
+	assert.Contains(t, rec.Body.String(), `&#34;n&#34;: 0`)
+
+	_, doc := call(http.MethodGet, "/openapi.json", "")
+	assert.Equal(t, []any{map[string]any{"url": "/actions"}}, doc["servers"])
+}
@@ -0,0 +1,167 @@
+// file: mini/input.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+)
+
+// ----------------------------------------------------
+// Typed input accessors
+// ----------------------------------------------------
+
+// ErrInputField is returned by Input accessors for a field the schema does
+// not declare with the requested type, a handler bug rather than bad input.
+var ErrInputField = errors.New("service: input field not declared with this type")
+
+// Input is a typed view of validated action input, built from the action
+// schema. Accessors return ErrInputField instead of a zero value when the
+// field is not declared or is declared with another type. A declared field
+// without a value yields the zero value and no error (see Has).
+type Input struct {
+	fields map[string]InputSchemaField
+	values map[string]any
+}
+
+type inputKey struct{}
+
+// NewInput returns a typed view of values, which should already have
+// passed validation against schema.
+func NewInput(schema []InputSchemaField, values map[string]any) *Input {
+	fields := make(map[string]InputSchemaField, len(schema))
+	for _, f := range schema {
+		fields[f.Name] = f
+	}
+	return &Input{fields: fields, values: values}
+}
+
+// InputFrom returns the typed input of the action being executed. Outside
+// an action it returns an Input with no declared fields.
+func InputFrom(ctx context.Context) *Input {
+	if in, ok := ctx.Value(inputKey{}).(*Input); ok {
+		return in
+	}
+	return NewInput(nil, nil)
+}
+
+func withInput(ctx context.Context, schema []InputSchemaField, values map[string]any) context.Context {
+	return context.WithValue(ctx, inputKey{}, NewInput(schema, values))
+}
+
+// Has reports whether the field has a value (given or defaulted).
+func (in *Input) Has(name string) bool {
+	v, ok := in.values[name]
+	return ok && !isEmpty(v)
+}
+
+func (in *Input) String(name string) (string, error) {
+	v, ok, err := in.lookup(name, TypeString)
+	if !ok || err != nil {
+		return "", err
+	}
+	s, _ := v.(string)
+	return s, nil
+}
+
+func (in *Input) Int(name string) (int64, error) {
+	v, ok, err := in.lookup(name, TypeInt, "integer")
+	if !ok || err != nil {
+		return 0, err
+	}
+	x, _ := v.(float64)
+	return int64(x), nil
+}
+
+func (in *Input) Number(name string) (float64, error) {
+	v, ok, err := in.lookup(name, TypeNumber, "float")
+	if !ok || err != nil {
+		return 0, err
+	}
+	x, _ := v.(float64)
+	return x, nil
+}
+
+func (in *Input) Bool(name string) (bool, error) {
+	v, ok, err := in.lookup(name, TypeBool, "boolean")
+	if !ok || err != nil {
+		return false, err
+	}
+	b, _ := v.(bool)
+	return b, nil
+}
+
+func (in *Input) Object(name string) (map[string]any, error) {
+	v, ok, err := in.lookup(name, TypeObject, "map")
+	if !ok || err != nil {
+		return nil, err
+	}
+	m, _ := v.(map[string]any)
+	return m, nil
+}
+
+func (in *Input) Array(name string) ([]any, error) {
+	v, ok, err := in.lookup(name, TypeArray)
+	if !ok || err != nil {
+		return nil, err
+	}
+	a, _ := v.([]any)
+	return a, nil
+}
+
+func (in *Input) Time(name string) (time.Time, error) {
+	v, ok, err := in.lookup(name, TypeTime)
+	if !ok || err != nil {
+		return time.Time{}, err
+	}
+	t, _ := codec.TimeValue(v)
+	return t, nil
+}
+
+func (in *Input) Duration(name string) (time.Duration, error) {
+	v, ok, err := in.lookup(name, TypeDuration)
+	if !ok || err != nil {
+		return 0, err
+	}
+	d, _ := codec.DurationValue(v)
+	return d, nil
+}
+
+func (in *Input) Decimal(name string) (codec.Decimal, error) {
+	v, ok, err := in.lookup(name, TypeDecimal)
+	if !ok || err != nil {
+		return "", err
+	}
+	d, _ := codec.DecimalValue(v)
+	return d, nil
+}
+
+// lookup returns the value of name, coerced like validateInput does, after
+// checking it is declared with one of types. ok is false when the field has
+// no value.
+func (in *Input) lookup(name string, types ...string) (v any, ok bool, err error) {
+	f, declared := in.fields[name]
+	if !declared {
+		return nil, false, fmt.Errorf("%w: %s is not in the schema", ErrInputField, name)
+	}
+	typ := strings.ToLower(f.Type)
+	match := false
+	for _, t := range types {
+		match = match || typ == t
+	}
+	if !match {
+		return nil, false, fmt.Errorf("%w: %s is %s, not %s", ErrInputField, name, f.Type, types[0])
+	}
+	v, ok = in.values[name]
+	if !ok || isEmpty(v) {
+		return nil, false, nil
+	}
+	if c, cok := coerce(types[0], v); cok {
+		v = c // defaults are stored as given
+	}
+	return v, true, nil
+}
@@ -0,0 +1,27 @@
+// file: mini/logger/field.go
+package logger
+
+import "time"
+
+// Field is a typed key/value attached to a log entry.
+type Field struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Field             { return Field{key, value} }
+func Int(key string, value int) Field            { return Field{key, value} }
+func Int64(key string, value int64) Field        { return Field{key, value} }
+func Float64(key string, value float64) Field    { return Field{key, value} }
+func Bool(key string, value bool) Field          { return Field{key, value} }
+func Duration(key string, d time.Duration) Field { return Field{key, d} }
+func Time(key string, t time.Time) Field         { return Field{key, t} }
+func Any(key string, value any) Field            { return Field{key, value} }
+
+// Err stores err under the "error" key; a nil error is stored as nil.
+func Err(err error) Field {
+	if err == nil {
+		return Field{"error", nil}
+	}
+	return Field{"error", err.Error()}
+}
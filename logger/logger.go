@@ -3,9 +3,10 @@ package logger
 
 import (
 	"fmt"
-	"log"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 var _ ILogger = (*Logger)(nil)
@@ -60,35 +61,116 @@ var levelOrder = map[string]int{
 
 type Logger struct {
 	service   string
+	component string
 	contextID string
-	level     string
+	level     *levelRef
+	shared    *shared
 }
 
-func NewLogger(serviceName, level string) ILogger {
-	return &Logger{
+// levelRef is the level of one logger. An empty value inherits the level of
+// the logger it was derived from, so SetLevel on a parent still reaches
+// derived loggers that never set their own.
+type levelRef struct {
+	value  string
+	parent *levelRef
+}
+
+// shared holds state common to a logger and everything derived from it,
+// so sinks and component levels can be changed at runtime. mu also guards
+// the level chain.
+type shared struct {
+	mu     sync.RWMutex
+	sinks  []ISink
+	levels map[string]string
+}
+
+// Option configures a Logger.
+type Option func(*Logger)
+
+// WithSinks replaces the default console sink.
+func WithSinks(sinks ...ISink) Option {
+	return func(l *Logger) { l.shared.sinks = sinks }
+}
+
+// WithComponentLevels sets per-component level overrides.
+func WithComponentLevels(levels map[string]string) Option {
+	return func(l *Logger) {
+		for c, lvl := range levels {
+			l.shared.levels[c] = normalizeLevel(lvl)
+		}
+	}
+}
+
+func NewLogger(serviceName, level string, opts ...Option) ILogger {
+	l := &Logger{
 		service: serviceName,
-		level:   normalizeLevel(level),
+		level:   &levelRef{value: normalizeLevel(level)},
+		shared: &shared{
+			sinks:  []ISink{ConsoleSink{}},
+			levels: make(map[string]string),
+		},
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
 }
 
+// SetLevel changes the level of this logger and of loggers derived from it
+// that have not set their own. It does not affect the logger this one was
+// derived from.
 func (l *Logger) SetLevel(level string) {
-	l.level = normalizeLevel(level)
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+	l.level.value = normalizeLevel(level)
 }
 
 func (l *Logger) WithContext(contextID string) ILogger {
-	return &Logger{
-		service:   l.service,
-		contextID: contextID,
-		level:     l.level,
-	}
+	c := l.copy()
+	c.contextID = contextID
+	return c
 }
 
 func (l *Logger) Clone() ILogger {
-	return &Logger{
-		service:   l.service,
-		contextID: l.contextID,
-		level:     l.level,
+	return l.copy()
+}
+
+// Component returns a logger for a named component. Its level can be
+// overridden with SetComponentLevel.
+func (l *Logger) Component(name string) ILogger {
+	c := l.copy()
+	c.component = name
+	return c
+}
+
+// SetComponentLevel overrides the level of a component at runtime for this
+// logger and all loggers derived from it. An empty level removes the override.
+func (l *Logger) SetComponentLevel(component, level string) {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+	if level == "" {
+		delete(l.shared.levels, component)
+		return
+	}
+	l.shared.levels[component] = normalizeLevel(level)
+}
+
+// ComponentLevels returns a copy of the component level overrides.
+func (l *Logger) ComponentLevels() map[string]string {
+	l.shared.mu.RLock()
+	defer l.shared.mu.RUnlock()
+	out := make(map[string]string, len(l.shared.levels))
+	for k, v := range l.shared.levels {
+		out[k] = v
 	}
+	return out
+}
+
+// AddSink adds an output for this logger and all loggers derived from it.
+func (l *Logger) AddSink(s ISink) {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+	l.shared.sinks = append(l.shared.sinks, s)
 }
 
 func (l *Logger) With(key string, value any) LoggerEntry {
@@ -98,20 +180,72 @@ func (l *Logger) With(key string, value any) LoggerEntry {
 	}
 }
 
-func (l *Logger) Debug(msg string, args ...any) { l.log(LevelDebug, msg, args...) }
-func (l *Logger) Info(msg string, args ...any)  { l.log(LevelInfo, msg, args...) }
-func (l *Logger) Warn(msg string, args ...any)  { l.log(LevelWarn, msg, args...) }
-func (l *Logger) Error(msg string, args ...any) { l.log(LevelError, msg, args...) }
+// WithFields returns an entry carrying typed fields.
+func (l *Logger) WithFields(fields ...Field) LoggerEntry {
+	e := &entry{parent: l, fields: make(map[string]any, len(fields))}
+	for _, f := range fields {
+		e.fields[f.Key] = f.Value
+	}
+	return e
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.log(LevelDebug, msg, nil, args...) }
+func (l *Logger) Info(msg string, args ...any)  { l.log(LevelInfo, msg, nil, args...) }
+func (l *Logger) Warn(msg string, args ...any)  { l.log(LevelWarn, msg, nil, args...) }
+func (l *Logger) Error(msg string, args ...any) { l.log(LevelError, msg, nil, args...) }
 
-func (l *Logger) log(level, msg string, args ...any) {
-	if !shouldLog(l.level, level) {
+func (l *Logger) log(level, msg string, fields map[string]any, args ...any) {
+	if !shouldLog(l.effectiveLevel(), level) {
 		return
 	}
-	prefix := fmt.Sprintf("[%s][%s]", strings.ToUpper(level), l.service)
-	if l.contextID != "" {
-		prefix += fmt.Sprintf("[cid:%s]", l.contextID)
+	rec := Record{
+		Time:      time.Now(),
+		Level:     level,
+		Service:   l.service,
+		Component: l.component,
+		ContextID: l.contextID,
+		Message:   fmt.Sprintf(msg, args...),
+		Fields:    fields,
 	}
-	log.Printf("%s %s", prefix, fmt.Sprintf(msg, args...))
+
+	l.shared.mu.RLock()
+	sinks := l.shared.sinks
+	l.shared.mu.RUnlock()
+	for _, s := range sinks {
+		_ = s.Write(rec)
+	}
+}
+
+// effectiveLevel applies the component override, if any.
+func (l *Logger) effectiveLevel() string {
+	l.shared.mu.RLock()
+	defer l.shared.mu.RUnlock()
+	if l.component != "" {
+		if lvl, ok := l.shared.levels[l.component]; ok {
+			return lvl
+		}
+	}
+	return l.level.resolve()
+}
+
+func (l *Logger) copy() *Logger {
+	return &Logger{
+		service:   l.service,
+		component: l.component,
+		contextID: l.contextID,
+		level:     &levelRef{parent: l.level},
+		shared:    l.shared,
+	}
+}
+
+// resolve returns the first level set up the chain. Callers hold shared.mu.
+func (lv *levelRef) resolve() string {
+	for ; lv != nil; lv = lv.parent {
+		if lv.value != "" {
+			return lv.value
+		}
+	}
+	return LevelInfo
 }
 
 // ----------------------------------------------------
@@ -137,45 +271,40 @@ func (e *entry) Clone() LoggerEntry {
 		copied[k] = v
 	}
 	return &entry{
-		parent: e.parent.Clone().(*Logger),
+		parent: e.parent.copy(),
 		fields: copied,
 	}
 }
 
-func (e *entry) Debug(msg string, args ...any) { e.log(LevelDebug, msg, args...) }
-func (e *entry) Info(msg string, args ...any)  { e.log(LevelInfo, msg, args...) }
-func (e *entry) Warn(msg string, args ...any)  { e.log(LevelWarn, msg, args...) }
-func (e *entry) Error(msg string, args ...any) { e.log(LevelError, msg, args...) }
+func (e *entry) Debug(msg string, args ...any) { e.parent.log(LevelDebug, msg, e.fields, args...) }
+func (e *entry) Info(msg string, args ...any)  { e.parent.log(LevelInfo, msg, e.fields, args...) }
+func (e *entry) Warn(msg string, args ...any)  { e.parent.log(LevelWarn, msg, e.fields, args...) }
+func (e *entry) Error(msg string, args ...any) { e.parent.log(LevelError, msg, e.fields, args...) }
 
-func (e *entry) log(level, msg string, args ...any) {
-	if !shouldLog(e.parent.level, level) {
-		return
-	}
-
-	prefix := fmt.Sprintf("[%s][%s]", strings.ToUpper(level), e.parent.service)
-	if e.parent.contextID != "" {
-		prefix += fmt.Sprintf("[cid:%s]", e.parent.contextID)
-	}
+// ----------------------------------------------------
+// Helpers
+// ----------------------------------------------------
 
-	// format fields
-	fieldParts := make([]string, 0, len(e.fields))
-	for k, v := range e.fields {
-		fieldParts = append(fieldParts, fmt.Sprintf("%s=%v", k, v))
+// Component returns a component logger when l supports it, otherwise l.
+func Component(l ILogger, name string) ILogger {
+	if c, ok := l.(interface{ Component(string) ILogger }); ok {
+		return c.Component(name)
 	}
-	sort.Strings(fieldParts)
+	return l
+}
 
-	meta := ""
-	if len(fieldParts) > 0 {
-		meta = " | " + strings.Join(fieldParts, " ")
+// ParseLevels parses "db=debug,http=warn" into component levels.
+func ParseLevels(spec string) map[string]string {
+	out := make(map[string]string)
+	for _, part := range strings.Split(spec, ",") {
+		c, lvl, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && c != "" {
+			out[strings.TrimSpace(c)] = normalizeLevel(strings.TrimSpace(lvl))
+		}
 	}
-
-	log.Printf("%s %s%s", prefix, fmt.Sprintf(msg, args...), meta)
+	return out
 }
 
-// ----------------------------------------------------
-// Helpers
-// ----------------------------------------------------
-
 func normalizeLevel(level string) string {
 	switch strings.ToLower(level) {
 	case LevelDebug, LevelInfo, LevelWarn, LevelError:
@@ -191,6 +320,18 @@ func shouldLog(current, incoming string) bool {
 	return i >= c
 }
 
+// formatFields renders fields as sorted "k=v" pairs.
+func formatFields(fields map[string]any) string {
+	parts := make([]string, 0, len(fields))
+	for k, v := range fields {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
 func (l *Logger) Level() string {
-	return l.level
+	l.shared.mu.RLock()
+	defer l.shared.mu.RUnlock()
+	return l.level.resolve()
 }
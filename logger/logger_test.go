@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"log"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/rskv-p/mini/logger"
@@ -59,6 +60,59 @@ func TestWithContextAndClone(t *testing.T) {
 	assert.Contains(t, output, "[INFO][svc][cid:ctx123] ctx present")
 }
 
+func TestSetLevel_DerivedLoggers(t *testing.T) {
+	root := logger.NewLogger("svc", "info").(*logger.Logger)
+	ctx := root.WithContext("ctx1")
+	comp := root.Component("db")
+
+	root.SetLevel("debug")
+	assert.Equal(t, "debug", root.Level())
+
+	output := captureOutput(func() {
+		ctx.Debug("ctx debug")
+		comp.Debug("comp debug")
+	})
+	assert.Contains(t, output, "ctx debug")
+	assert.Contains(t, output, "comp debug")
+}
+
+func TestSetLevel_CloneKeepsOwnLevel(t *testing.T) {
+	root := logger.NewLogger("svc", "info").(*logger.Logger)
+	cl := root.Clone().(*logger.Logger)
+	ctx := cl.WithContext("ctx1").(*logger.Logger)
+
+	cl.SetLevel("error")
+	assert.Equal(t, "info", root.Level())
+	assert.Equal(t, "error", cl.Level())
+	assert.Equal(t, "error", ctx.Level())
+
+	root.SetLevel("debug")
+	assert.Equal(t, "error", cl.Level())
+	assert.Equal(t, "debug", root.WithContext("ctx2").(*logger.Logger).Level())
+}
+
+func TestSetLevel_Concurrent(t *testing.T) {
+	l := logger.NewLogger("svc", "error", logger.WithSinks()).(*logger.Logger)
+	derived := l.WithContext("ctx")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.SetLevel("warn")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			derived.Info("tick")
+		}
+	}()
+	wg.Wait()
+	assert.Equal(t, "warn", l.Level())
+}
+
 func TestLoggerEntryFields(t *testing.T) {
 	l := logger.NewLogger("svc", "debug")
 	entry := l.With("k1", "v1").With("k2", 42)
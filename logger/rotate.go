@@ -0,0 +1,114 @@
+// file: mini/logger/rotate.go
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ----------------------------------------------------
+// Size-based rotating file
+// ----------------------------------------------------
+
+var _ io.WriteCloser = (*RotatingFile)(nil)
+
+// RotatingFile appends to path and, once a write would take it past
+// maxBytes, rotates it to path.1 … path.N first. Each Write lands whole in
+// one file, so callers writing one record per call never split a record.
+type RotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens (or creates) path for appending.
+// maxBytes <= 0 disables rotation; maxBackups <= 0 keeps one backup.
+func NewRotatingFile(path string, maxBytes int64, maxBackups int) (*RotatingFile, error) {
+	if maxBackups <= 0 {
+		maxBackups = 1
+	}
+	f := &RotatingFile{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first if needed.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.maxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the underlying file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// Path returns the active file path.
+func (f *RotatingFile) Path() string {
+	return f.path
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate closes the active file, shifts the backups and reopens path. When
+// a step fails the original file is reopened, so the writer is never left
+// closed.
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return errors.Join(err, f.open())
+	}
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return errors.Join(err, f.open())
+	}
+	return f.open()
+}
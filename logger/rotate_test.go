@@ -0,0 +1,59 @@
+// file: mini/logger/rotate_test.go
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rskv-p/mini/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "data.log")
+	f, err := logger.NewRotatingFile(path, 10, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, path, f.Path())
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err = f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.Close())
+
+	cur, _ := os.ReadFile(path)
+	prev, _ := os.ReadFile(path + ".1")
+	assert.Equal(t, "third\n", string(cur))
+	assert.Equal(t, "second\n", string(prev))
+	_, err = os.Stat(path + ".2")
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = f.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotatingFile_ReopensOnFailedRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.log")
+	f, err := logger.NewRotatingFile(path, 10, 1)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	// a non-empty directory at path.1 makes the rename fail
+	assert.NoError(t, os.MkdirAll(filepath.Join(path+".1", "x"), 0755))
+
+	_, err = f.Write([]byte("first\n"))
+	assert.NoError(t, err)
+	_, err = f.Write([]byte("second\n"))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, os.ErrClosed)
+
+	assert.NoError(t, os.RemoveAll(path+".1"))
+	_, err = f.Write([]byte("third\n"))
+	assert.NoError(t, err)
+
+	cur, _ := os.ReadFile(path)
+	prev, _ := os.ReadFile(path + ".1")
+	assert.Equal(t, "third\n", string(cur))
+	assert.Equal(t, "first\n", string(prev))
+}
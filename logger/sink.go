@@ -0,0 +1,114 @@
+// file: mini/logger/sink.go
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------
+// Records and sinks
+// ----------------------------------------------------
+
+// Record is one log event as delivered to sinks.
+type Record struct {
+	Time      time.Time      `json:"time"`
+	Level     string         `json:"level"`
+	Service   string         `json:"service"`
+	Component string         `json:"component,omitempty"`
+	ContextID string         `json:"context_id,omitempty"`
+	Message   string         `json:"msg"`
+	Fields    map[string]any `json:"fields,omitempty"`
+}
+
+// ISink receives log records.
+type ISink interface {
+	Write(Record) error
+}
+
+var (
+	_ ISink = ConsoleSink{}
+	_ ISink = (*JSONSink)(nil)
+	_ ISink = (*FileSink)(nil)
+	_ ISink = FuncSink(nil)
+)
+
+// ConsoleSink writes human-readable lines through the standard log package.
+type ConsoleSink struct{}
+
+func (ConsoleSink) Write(r Record) error {
+	name := r.Service
+	if r.Component != "" {
+		name += "/" + r.Component
+	}
+	prefix := fmt.Sprintf("[%s][%s]", strings.ToUpper(r.Level), name)
+	if r.ContextID != "" {
+		prefix += fmt.Sprintf("[cid:%s]", r.ContextID)
+	}
+	meta := ""
+	if len(r.Fields) > 0 {
+		meta = " | " + formatFields(r.Fields)
+	}
+	log.Printf("%s %s%s", prefix, r.Message, meta)
+	return nil
+}
+
+// FuncSink adapts a function, e.g. one shipping records to the bus.
+type FuncSink func(Record) error
+
+func (f FuncSink) Write(r Record) error { return f(r) }
+
+// JSONSink writes one JSON object per line to w.
+type JSONSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONSink returns a sink writing JSON lines to w.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: w}
+}
+
+func (s *JSONSink) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// ----------------------------------------------------
+// File sink with size-based rotation
+// ----------------------------------------------------
+
+// FileSink appends JSON lines to a RotatingFile.
+type FileSink struct {
+	*RotatingFile
+}
+
+// NewFileSink opens (or creates) path for appending; see NewRotatingFile
+// for the rotation limits.
+func NewFileSink(path string, maxBytes int64, maxBackups int) (*FileSink, error) {
+	f, err := NewRotatingFile(path, maxBytes, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{RotatingFile: f}, nil
+}
+
+func (s *FileSink) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.RotatingFile.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,103 @@
+// file: mini/logger/sink_test.go
+package logger_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedFields_JSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.NewLogger("svc", "debug", logger.WithSinks(logger.NewJSONSink(&buf))).(*logger.Logger)
+
+	l.WithFields(
+		logger.String("user", "bob"),
+		logger.Int("attempt", 2),
+		logger.Duration("took", time.Second),
+		logger.Err(errors.New("boom")),
+	).Warn("call failed")
+
+	var rec logger.Record
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "warn", rec.Level)
+	assert.Equal(t, "call failed", rec.Message)
+	assert.Equal(t, "bob", rec.Fields["user"])
+	assert.Equal(t, float64(2), rec.Fields["attempt"])
+	assert.Equal(t, "boom", rec.Fields["error"])
+}
+
+func TestComponentLevels(t *testing.T) {
+	var got []logger.Record
+	sink := logger.FuncSink(func(r logger.Record) error {
+		got = append(got, r)
+		return nil
+	})
+	root := logger.NewLogger("svc", "info",
+		logger.WithSinks(sink),
+		logger.WithComponentLevels(map[string]string{"db": "debug"}),
+	).(*logger.Logger)
+
+	db := logger.Component(root, "db")
+	http := logger.Component(root, "http")
+
+	db.Debug("query")
+	http.Debug("hidden")
+	assert.Len(t, got, 1)
+	assert.Equal(t, "db", got[0].Component)
+
+	// runtime change applies to already-derived loggers
+	root.SetComponentLevel("http", "debug")
+	root.SetComponentLevel("db", "")
+	http.Debug("shown")
+	db.Debug("hidden again")
+	assert.Len(t, got, 2)
+	assert.Equal(t, "shown", got[1].Message)
+}
+
+func TestParseLevels(t *testing.T) {
+	assert.Equal(t, map[string]string{"db": "debug", "http": "warn"},
+		logger.ParseLevels("db=debug, http=WARN, bad"))
+	assert.Empty(t, logger.ParseLevels(""))
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	sink, err := logger.NewFileSink(path, 200, 2)
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	l := logger.NewLogger("svc", "info", logger.WithSinks(sink))
+	for i := 0; i < 10; i++ {
+		l.Info("message number %d", i)
+	}
+
+	_, err = os.Stat(path + ".1")
+	assert.NoError(t, err)
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	f, _ := os.Open(path)
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec logger.Record
+		assert.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+	}
+}
+
+func TestConsoleSink_Component(t *testing.T) {
+	l := logger.Component(logger.NewLogger("svc", "info"), "db")
+	output := captureOutput(func() {
+		l.Info("ready")
+	})
+	assert.Contains(t, output, "[INFO][svc/db] ready")
+}
@@ -2,6 +2,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -33,11 +34,18 @@ func (s *Service) Respond(msg codec.IMessage, subject string) error {
 
 // Pub sends a one-way message to a selected node.
 func (s *Service) Pub(service string, msg codec.IMessage) error {
+	return s.PubWithContext(context.Background(), service, msg)
+}
+
+// PubWithContext is Pub with correlation and trace headers taken from ctx.
+func (s *Service) PubWithContext(ctx context.Context, service string, msg codec.IMessage) error {
 	nodeID, err := s.opts.Selector.Select(service)
 	if err != nil {
 		return err
 	}
 	msg.SetType(constant.MessageTypePublish)
+	transport.ApplyCorrelation(ctx, msg)
+	transport.ApplyTraceContext(ctx, msg)
 
 	data, err := codec.Marshal(msg)
 	if err != nil {
@@ -46,17 +54,28 @@ func (s *Service) Pub(service string, msg codec.IMessage) error {
 
 	retries, interval := s.retryConfig()
 	return s.retrySend("Pub", retries, interval, func() error {
-		return s.opts.Transport.Publish(nodeID, data)
+		start := time.Now()
+		err := s.opts.Transport.Publish(nodeID, data)
+		s.opts.Selector.Report(service, nodeID, err, time.Since(start))
+		return err
 	})
 }
 
 // Req sends a request and waits for a response via handler.
 func (s *Service) Req(service string, msg codec.IMessage, handler transport.ResponseHandler) error {
+	return s.ReqWithContext(context.Background(), service, msg, handler)
+}
+
+// ReqWithContext is Req with correlation and trace headers taken from ctx.
+// Pass the context received by an action to link downstream calls to it.
+func (s *Service) ReqWithContext(ctx context.Context, service string, msg codec.IMessage, handler transport.ResponseHandler) error {
 	nodeID, err := s.opts.Selector.Select(service)
 	if err != nil {
 		return err
 	}
 	msg.SetType(constant.MessageTypeRequest)
+	transport.ApplyCorrelation(ctx, msg)
+	transport.ApplyTraceContext(ctx, msg)
 
 	data, err := codec.Marshal(msg)
 	if err != nil {
@@ -65,7 +84,23 @@ func (s *Service) Req(service string, msg codec.IMessage, handler transport.Resp
 
 	retries, interval := s.retryConfig()
 	return s.retrySend("Req", retries, interval, func() error {
-		return s.opts.Transport.Request(nodeID, data, handler)
+		// Errors from the caller's handler say nothing about the node.
+		var handlerErr error
+		wrapped := handler
+		if handler != nil {
+			wrapped = func(m codec.IMessage) error {
+				handlerErr = handler(m)
+				return handlerErr
+			}
+		}
+		start := time.Now()
+		err := s.opts.Transport.RequestWithContext(ctx, nodeID, data, wrapped)
+		nodeErr := err
+		if handlerErr != nil && errors.Is(err, handlerErr) {
+			nodeErr = nil
+		}
+		s.opts.Selector.Report(service, nodeID, nodeErr, time.Since(start))
+		return err
 	})
 }
 
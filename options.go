@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/config"
 	"github.com/rskv-p/mini/context"
 	"github.com/rskv-p/mini/logger"
 	"github.com/rskv-p/mini/registry"
@@ -42,6 +43,7 @@ type Hooks struct {
 // ----------------------------------------------------
 
 type Options struct {
+	Config    config.IConfig
 	Transport transport.ITransport
 	Registry  registry.IRegistry
 	Router    router.IRouter
@@ -53,6 +55,19 @@ type Options struct {
 
 	HdlrWrappers []HandlerWrapper
 	Debug        bool
+
+	HTTP             bool
+	HTTPAddr         string
+	HTTPDocs         bool
+	HTTPActions      bool
+	HTTPStaticDir    string
+	HTTPStaticPrefix string
+	HTTPAuthToken    string
+
+	RequestLogging *RequestLogConfig
+	ConfigReload   bool
+	Describe       bool
+	Chaos          []transport.ChaosRule
 }
 
 // Option defines a configuration function.
@@ -99,6 +114,13 @@ func newOptions(opts ...Option) Options {
 // Option constructors
 // ----------------------------------------------------
 
+// WithConfig makes the service run with cfg instead of the default
+// config.New(config.FromEnv("SRV_")), e.g. one built with
+// config.FromProfile.
+func WithConfig(cfg config.IConfig) Option {
+	return func(o *Options) { o.Config = cfg }
+}
+
 func Transport(t transport.ITransport) Option {
 	return func(o *Options) { o.Transport = t }
 }
@@ -144,6 +166,67 @@ func EnableDebug() Option {
 	return func(o *Options) { o.Debug = true }
 }
 
+// WithRequestLogging enables the built-in request logging middleware.
+func WithRequestLogging(cfg RequestLogConfig) Option {
+	return func(o *Options) { o.RequestLogging = &cfg }
+}
+
+// EnableConfigReload reloads config on SIGHUP and registers the
+// config.reload action.
+func EnableConfigReload() Option {
+	return func(o *Options) { o.ConfigReload = true }
+}
+
+// EnableDescribe registers the router.describe action, which returns the
+// JSON Schema used to validate a given action.
+func EnableDescribe() Option {
+	return func(o *Options) { o.Describe = true }
+}
+
+// EnableHTTP serves /healthz, /readyz and /metrics over HTTP.
+// An empty addr falls back to the "port" config key.
+func EnableHTTP(addr string) Option {
+	return func(o *Options) {
+		o.HTTP = true
+		o.HTTPAddr = addr
+	}
+}
+
+// EnableChaos injects faults per rule into transport traffic, matching
+// rules against the subject. Rules only take effect when the "dev_mode"
+// config key is true.
+func EnableChaos(rules ...transport.ChaosRule) Option {
+	return func(o *Options) { o.Chaos = append(o.Chaos, rules...) }
+}
+
+// EnableHTTPDocs serves /openapi.json, /docs and /docs/services on the
+// HTTP listener (see EnableHTTP).
+func EnableHTTPDocs() Option {
+	return func(o *Options) { o.HTTPDocs = true }
+}
+
+// EnableHTTPActions serves POST /actions/<name>, which runs a registered
+// action in-process with the JSON request body as input, and adds a try-it
+// form per action to /docs. Combine it with WithHTTPAuth outside trusted
+// networks.
+func EnableHTTPActions() Option {
+	return func(o *Options) { o.HTTPActions = true }
+}
+
+// WithHTTPStatic serves files from dir under prefix (default "/static/").
+func WithHTTPStatic(prefix, dir string) Option {
+	return func(o *Options) {
+		o.HTTPStaticPrefix = prefix
+		o.HTTPStaticDir = dir
+	}
+}
+
+// WithHTTPAuth protects docs, action and static routes with a bearer token.
+// Probes and /metrics stay open.
+func WithHTTPAuth(token string) Option {
+	return func(o *Options) { o.HTTPAuthToken = token }
+}
+
 // ----------------------------------------------------
 // Utility methods
 // ----------------------------------------------------
@@ -0,0 +1,120 @@
+// file: mini/reload.go
+package service
+
+import (
+	"context"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/logger"
+)
+
+// ----------------------------------------------------
+// Config reload (SIGHUP / config.reload action)
+// ----------------------------------------------------
+
+const (
+	// ConfigReloadAction is the built-in action name that triggers a reload.
+	ConfigReloadAction = "config.reload"
+	// ConfigReloadedSubject receives an event after every successful reload.
+	ConfigReloadedSubject = "config.reloaded"
+	// ConfigInfoAction is the built-in action that reports the active profile.
+	ConfigInfoAction = "cfg.info"
+)
+
+// restartKeys are settings that only take effect after a restart.
+var restartKeys = map[string]bool{
+	"service_name": true,
+	"bus_addr":     true,
+	"port":         true,
+}
+
+// ReloadResult reports the outcome of a config reload. Only key names are
+// reported: the result is returned to any caller of config.reload, and the
+// values may be secrets.
+type ReloadResult struct {
+	Changes         []string `json:"changes"`
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ReloadConfig re-reads the config, applies hot-reloadable settings in
+// place and reports which changed keys need a restart.
+func (s *Service) ReloadConfig() (ReloadResult, error) {
+	changes, err := s.config.Reload()
+	if err != nil {
+		s.logger.Error("config reload failed: %v", err)
+		return ReloadResult{}, err
+	}
+
+	var res ReloadResult
+	for _, ch := range changes {
+		res.Changes = append(res.Changes, ch.Key)
+		if restartKeys[ch.Key] {
+			res.RestartRequired = append(res.RestartRequired, ch.Key)
+			continue
+		}
+		switch ch.Key {
+		case "log_level":
+			s.logger.SetLevel(s.config.MustString("log_level"))
+		case "log_levels":
+			s.applyLogLevels(s.config.MustString("log_levels"))
+		}
+		res.Applied = append(res.Applied, ch.Key)
+	}
+
+	s.logger.Info("config reloaded: %d changed, %d applied, %d need restart",
+		len(changes), len(res.Applied), len(res.RestartRequired))
+	s.publishReloadEvent(res)
+	return res, nil
+}
+
+// applyLogLevels replaces per-component log levels ("db=debug,http=warn").
+func (s *Service) applyLogLevels(spec string) {
+	l, ok := s.logger.(*logger.Logger)
+	if !ok {
+		return
+	}
+	levels := logger.ParseLevels(spec)
+	for c := range l.ComponentLevels() {
+		if _, keep := levels[c]; !keep {
+			l.SetComponentLevel(c, "")
+		}
+	}
+	for c, lvl := range levels {
+		l.SetComponentLevel(c, lvl)
+	}
+}
+
+// publishReloadEvent emits a config.reloaded event for observability.
+func (s *Service) publishReloadEvent(res ReloadResult) {
+	if s.opts.Transport == nil || len(res.Changes) == 0 {
+		return
+	}
+	msg := codec.NewMessage(constant.MessageTypeEvent)
+	msg.Set("service", s.name)
+	msg.Set("id", s.id)
+	msg.Set("applied", res.Applied)
+	msg.Set("restart_required", res.RestartRequired)
+
+	data, err := codec.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := s.opts.Transport.Publish(ConfigReloadedSubject, data); err != nil {
+		s.logger.Warn("publish %s: %v", ConfigReloadedSubject, err)
+	}
+}
+
+// reloadAction exposes ReloadConfig as an action.
+func (s *Service) reloadAction(ctx context.Context, _ map[string]any) (any, error) {
+	return s.ReloadConfig()
+}
+
+// configInfoAction reports the active config profile and the common keys.
+func (s *Service) configInfoAction(context.Context, map[string]any) (any, error) {
+	return map[string]any{
+		"profile": s.config.Profile(),
+		"config":  s.Config(),
+	}, nil
+}
@@ -0,0 +1,118 @@
+// file: mini/reload_test.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/rskv-p/mini/logger"
+	"github.com/rskv-p/mini/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+type levelLogger struct {
+	testLogger
+	level string
+}
+
+func (l *levelLogger) SetLevel(level string) { l.level = level }
+
+func TestReloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_level": "info", "port": "80"}`), 0644))
+
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	log := &levelLogger{}
+	s := &Service{
+		name:   "reload",
+		config: cfg,
+		logger: log,
+		opts:   Options{Transport: transport.New()},
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_level": "debug", "port": "81", "hc_load_warning": "2"}`), 0644))
+	res, err := s.ReloadConfig()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "debug", log.level)
+	assert.ElementsMatch(t, []string{"log_level", "hc_load_warning"}, res.Applied)
+	assert.Equal(t, []string{"port"}, res.RestartRequired)
+	assert.ElementsMatch(t, []string{"log_level", "port", "hc_load_warning"}, res.Changes)
+}
+
+func TestReloadAction_OmitsValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"api_token": "old-secret"}`), 0644))
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	s := newInitService(WithConfig(cfg), EnableConfigReload())
+	assert.NoError(t, s.Init())
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"api_token": "new-secret"}`), 0644))
+	out, err := s.actions[ConfigReloadAction].handler(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api_token"}, out.(ReloadResult).Changes)
+
+	data, _ := json.Marshal(out)
+	assert.NotContains(t, string(data), "secret")
+}
+
+func TestReloadAction_Registered(t *testing.T) {
+	cfg, _ := config.New()
+	s := newInitService()
+	s.config = cfg
+	assert.NoError(t, s.Init())
+	assert.NotContains(t, s.ListActions(), ConfigReloadAction)
+
+	s = newInitService(EnableConfigReload())
+	s.config = cfg
+	assert.NoError(t, s.Init())
+	assert.Contains(t, s.ListActions(), ConfigReloadAction)
+
+	out, err := s.actions[ConfigReloadAction].handler(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, out.(ReloadResult).Changes)
+}
+
+func TestReloadConfig_ComponentLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_levels": "db=debug,http=warn"}`), 0644))
+	cfg, err := config.New(config.FromJSON(path))
+	assert.NoError(t, err)
+
+	log := logger.NewLogger("reload", "info", logger.WithComponentLevels(logger.ParseLevels(cfg.MustString("log_levels")))).(*logger.Logger)
+	s := &Service{name: "reload", config: cfg, logger: log, opts: Options{Transport: transport.New()}}
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"log_levels": "db=error"}`), 0644))
+	res, err := s.ReloadConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log_levels"}, res.Applied)
+	assert.Equal(t, map[string]string{"db": "error"}, log.ComponentLevels())
+}
+
+func TestConfigInfoAction(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "base.json"), []byte(`{"log_level": "info"}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "staging.json"), []byte(`{"Log_Level": "warn"}`), 0644))
+	t.Setenv("TEST_PROFILE", "staging")
+	cfg, err := config.New(config.FromProfile(dir, "TEST_PROFILE"))
+	assert.NoError(t, err)
+
+	s := newInitService(WithConfig(cfg))
+	assert.NoError(t, s.Init())
+	assert.Contains(t, s.ListActions(), ConfigInfoAction)
+	assert.Same(t, cfg, s.Options().Config)
+
+	out, err := s.actions[ConfigInfoAction].handler(context.Background(), nil)
+	assert.NoError(t, err)
+	info := out.(map[string]any)
+	assert.Equal(t, "staging", info["profile"])
+	assert.Equal(t, "warn", info["config"].(map[string]string)["log_level"])
+}
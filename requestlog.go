@@ -0,0 +1,114 @@
+// file: mini/requestlog.go
+package service
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/rskv-p/mini/logger"
+	"github.com/rskv-p/mini/transport"
+)
+
+// ----------------------------------------------------
+// Request logging middleware
+// ----------------------------------------------------
+
+// redactedValue replaces values of redacted fields.
+const redactedValue = "***"
+
+// RequestLogConfig controls the built-in request logging middleware.
+type RequestLogConfig struct {
+	SampleRate float64  // Fraction of successful requests logged (0 or ≥1 = all)
+	Redact     []string // Input field names masked in logs (case-insensitive, nested)
+	LogInput   bool     // Include the (redacted) input in the log entry
+}
+
+// RequestLogging logs action, latency, raw payload size, status and W3C
+// trace ID for each request. Failed requests are always logged regardless
+// of sampling.
+func RequestLogging(log logger.ILogger, cfg RequestLogConfig) Middleware {
+	redact := make(map[string]struct{}, len(cfg.Redact))
+	for _, f := range cfg.Redact {
+		redact[strings.ToLower(f)] = struct{}{}
+	}
+
+	return func(next ActionFunc) ActionFunc {
+		return func(ctx context.Context, input map[string]any) (any, error) {
+			start := time.Now()
+			result, err := next(ctx, input)
+
+			if err == nil && !sampled(cfg.SampleRate) {
+				return result, err
+			}
+
+			status := "ok"
+			if err != nil {
+				status = "error"
+			}
+
+			entry := log.WithContext(ContextIDFrom(ctx)).
+				With("action", ActionNameFrom(ctx)).
+				With("latency_ms", time.Since(start).Milliseconds()).
+				With("size", PayloadSizeFrom(ctx)).
+				With("status", status).
+				With("trace_id", requestTraceID(ctx))
+			if cfg.LogInput {
+				entry = entry.With("input", redactMap(input, redact))
+			}
+
+			if err != nil {
+				entry.With("error", err.Error()).Warn("request failed")
+			} else {
+				entry.Info("request handled")
+			}
+			return result, err
+		}
+	}
+}
+
+// sampled reports whether the current request should be logged.
+func sampled(rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+// requestTraceID returns the trace ID of the request's W3C trace context,
+// falling back to the message trace_id.
+func requestTraceID(ctx context.Context) string {
+	if tp, ok := transport.TraceParentFromContext(ctx); ok && tp.IsValid() {
+		return tp.TraceID
+	}
+	return TraceIDFrom(ctx)
+}
+
+// redactMap returns a copy of in with redacted keys masked at any depth,
+// including maps inside arrays.
+func redactMap(in map[string]any, keys map[string]struct{}) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		if _, ok := keys[strings.ToLower(k)]; ok {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = redactValue(v, keys)
+	}
+	return out
+}
+
+func redactValue(v any, keys map[string]struct{}) any {
+	switch x := v.(type) {
+	case map[string]any:
+		return redactMap(x, keys)
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = redactValue(e, keys)
+		}
+		return out
+	}
+	return v
+}
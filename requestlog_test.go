@@ -0,0 +1,144 @@
+// file: mini/requestlog_test.go
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rskv-p/mini/config"
+	"github.com/rskv-p/mini/logger"
+	"github.com/rskv-p/mini/transport"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------
+// Recording logger
+// ----------------------------------------------------
+
+type recordedLog struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+type recordingLogger struct {
+	testLogger
+	logs *[]recordedLog
+}
+
+func (l *recordingLogger) WithContext(string) logger.ILogger { return l }
+func (l *recordingLogger) With(key string, value any) logger.LoggerEntry {
+	return (&recordingEntry{logs: l.logs, fields: map[string]any{}}).With(key, value)
+}
+
+type recordingEntry struct {
+	logs   *[]recordedLog
+	fields map[string]any
+}
+
+func (e *recordingEntry) With(key string, value any) logger.LoggerEntry {
+	e.fields[key] = value
+	return e
+}
+func (e *recordingEntry) Clone() logger.LoggerEntry { return e }
+func (e *recordingEntry) Debug(msg string, args ...any) {
+	*e.logs = append(*e.logs, recordedLog{"debug", msg, e.fields})
+}
+func (e *recordingEntry) Info(msg string, args ...any) {
+	*e.logs = append(*e.logs, recordedLog{"info", msg, e.fields})
+}
+func (e *recordingEntry) Warn(msg string, args ...any) {
+	*e.logs = append(*e.logs, recordedLog{"warn", msg, e.fields})
+}
+func (e *recordingEntry) Error(msg string, args ...any) {
+	*e.logs = append(*e.logs, recordedLog{"error", msg, e.fields})
+}
+
+// ----------------------------------------------------
+// Tests
+// ----------------------------------------------------
+
+func TestRequestLogging_Fields(t *testing.T) {
+	var logs []recordedLog
+	log := &recordingLogger{logs: &logs}
+
+	mw := RequestLogging(log, RequestLogConfig{
+		Redact:   []string{"password"},
+		LogInput: true,
+	})
+	h := mw(func(ctx context.Context, in map[string]any) (any, error) { return "ok", nil })
+
+	tp, _ := transport.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := context.WithValue(context.Background(), ActionNameKey, "auth.login")
+	ctx = context.WithValue(ctx, TraceIDKey, "legacy-trace")
+	ctx = context.WithValue(ctx, PayloadSizeKey, 123)
+	ctx = transport.WithTraceParent(ctx, tp)
+	_, err := h(ctx, map[string]any{
+		"email":    "a@b.c",
+		"password": "secret",
+		"nested":   map[string]any{"Password": "x"},
+		"users":    []any{map[string]any{"name": "bob", "password": "y"}, "plain"},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, logs, 1)
+	f := logs[0].fields
+	assert.Equal(t, "info", logs[0].level)
+	assert.Equal(t, "auth.login", f["action"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", f["trace_id"])
+	assert.Equal(t, "ok", f["status"])
+	assert.Equal(t, 123, f["size"])
+
+	input := f["input"].(map[string]any)
+	assert.Equal(t, "***", input["password"])
+	assert.Equal(t, "a@b.c", input["email"])
+	assert.Equal(t, "***", input["nested"].(map[string]any)["Password"])
+	users := input["users"].([]any)
+	assert.Equal(t, map[string]any{"name": "bob", "password": "***"}, users[0])
+	assert.Equal(t, "plain", users[1])
+}
+
+func TestRequestLogging_TraceIDFallback(t *testing.T) {
+	var logs []recordedLog
+	mw := RequestLogging(&recordingLogger{logs: &logs}, RequestLogConfig{})
+	h := mw(func(ctx context.Context, in map[string]any) (any, error) { return nil, nil })
+
+	_, _ = h(context.WithValue(context.Background(), TraceIDKey, "legacy-trace"), nil)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "legacy-trace", logs[0].fields["trace_id"])
+	assert.Equal(t, 0, logs[0].fields["size"])
+}
+
+func TestRequestLogging_SamplingKeepsErrors(t *testing.T) {
+	var logs []recordedLog
+	log := &recordingLogger{logs: &logs}
+
+	mw := RequestLogging(log, RequestLogConfig{SampleRate: 0.0000001})
+	ok := mw(func(ctx context.Context, in map[string]any) (any, error) { return nil, nil })
+	fail := mw(func(ctx context.Context, in map[string]any) (any, error) { return nil, errors.New("boom") })
+
+	for i := 0; i < 20; i++ {
+		_, _ = ok(context.Background(), nil)
+	}
+	_, err := fail(context.Background(), nil)
+	assert.Error(t, err)
+
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "warn", logs[0].level)
+	assert.Equal(t, "boom", logs[0].fields["error"])
+}
+
+func TestRequestLogging_InstalledOnce(t *testing.T) {
+	s := newInitService(WithRequestLogging(RequestLogConfig{}))
+	s.config, _ = config.New()
+	assert.NoError(t, s.Init())
+	assert.NoError(t, s.Init())
+	assert.Len(t, s.middlewares, 1)
+}
+
+func TestSampled(t *testing.T) {
+	assert.True(t, sampled(0))
+	assert.True(t, sampled(1))
+	assert.True(t, sampled(2))
+}
@@ -27,6 +27,10 @@ type Error struct {
 	Message    string
 }
 
+// Error implements error, so action handlers can return it to choose the
+// response status.
+func (e *Error) Error() string { return e.Message }
+
 // Node represents a route registration.
 type Node struct {
 	ID                 string              `json:"id"`
@@ -52,6 +56,7 @@ type IRouter interface {
 	RegisterActions([]IAction)
 	RegisterActionsFromStructs(any)
 	Dispatch(codec.IMessage) (Handler, error)
+	Describe(nodeID string) (map[string]any, error)
 	Register() error
 	Deregister() error
 	GetOptions() Options
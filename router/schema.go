@@ -0,0 +1,110 @@
+// file: mini/router/schema.go
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rskv-p/mini/constant"
+)
+
+// ----------------------------------------------------
+// JSON Schema from validation rules
+// ----------------------------------------------------
+
+// Schema returns a JSON Schema object describing the node's validation
+// rules. A "type:" rule is a documentation hint and is not enforced; without
+// one, min/max map to both minimum and minLength, matching how they are
+// checked against numbers and strings.
+func (n *Node) Schema() map[string]any {
+	fields := make([]string, 0, len(n.ValidationRules))
+	for f := range n.ValidationRules {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	properties := make(map[string]any, len(fields))
+	required := []string{}
+	for _, field := range fields {
+		prop := map[string]any{}
+		typ := ruleType(n.ValidationRules[field])
+		if typ != "" {
+			prop["type"] = typ
+		}
+		for _, rule := range n.ValidationRules[field] {
+			switch {
+			case rule == "required":
+				required = append(required, field)
+			case strings.HasPrefix(rule, "min:"):
+				setBound(prop, typ, "minimum", "minLength", strings.TrimPrefix(rule, "min:"))
+			case strings.HasPrefix(rule, "max:"):
+				setBound(prop, typ, "maximum", "maxLength", strings.TrimPrefix(rule, "max:"))
+			}
+		}
+		if msg, ok := n.ValidationMessages[field]; ok {
+			prop["description"] = msg
+		}
+		properties[field] = prop
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// Describe returns the JSON Schema used to validate nodeID.
+func (r *Router) Describe(nodeID string) (map[string]any, error) {
+	n, ok := r.routes[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", constant.ErrNotFound, nodeID)
+	}
+	return n.Schema(), nil
+}
+
+// ruleType returns the JSON Schema type from a "type:<name>" rule.
+func ruleType(rules []string) string {
+	for _, rule := range rules {
+		if strings.HasPrefix(rule, "type:") {
+			return schemaType(strings.TrimPrefix(rule, "type:"))
+		}
+	}
+	return ""
+}
+
+func schemaType(name string) string {
+	switch strings.ToLower(name) {
+	case "int", "integer":
+		return "integer"
+	case "float", "number":
+		return "number"
+	case "bool", "boolean":
+		return "boolean"
+	case "map", "object":
+		return "object"
+	default:
+		return strings.ToLower(name)
+	}
+}
+
+func setBound(prop map[string]any, typ, numKey, lenKey, raw string) {
+	limit, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return
+	}
+	switch typ {
+	case "string":
+		prop[lenKey] = int(limit)
+	case "number", "integer":
+		prop[numKey] = limit
+	case "":
+		prop[numKey] = limit
+		prop[lenKey] = int(limit)
+	}
+}
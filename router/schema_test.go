@@ -0,0 +1,64 @@
+// file: mini/router/schema_test.go
+package router_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func noop(context.Context, codec.IMessage, string) *router.Error { return nil }
+
+func TestNodeSchema(t *testing.T) {
+	n := &router.Node{
+		ID:      "user.create",
+		Handler: noop,
+		ValidationRules: map[string][]string{
+			"name": {"required", "type:string", "min:3", "max:32"},
+			"age":  {"type:int", "min:18"},
+			"code": {"min:1"},
+		},
+		ValidationMessages: map[string]string{"name": "display name"},
+	}
+
+	schema := n.Schema()
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"name"}, schema["required"])
+
+	props := schema["properties"].(map[string]any)
+	name := props["name"].(map[string]any)
+	assert.Equal(t, "string", name["type"])
+	assert.Equal(t, 3, name["minLength"])
+	assert.Equal(t, 32, name["maxLength"])
+	assert.Equal(t, "display name", name["description"])
+
+	age := props["age"].(map[string]any)
+	assert.Equal(t, "integer", age["type"])
+	assert.Equal(t, 18.0, age["minimum"])
+
+	code := props["code"].(map[string]any)
+	assert.NotContains(t, code, "type")
+	assert.Equal(t, 1.0, code["minimum"])
+	assert.Equal(t, 1, code["minLength"])
+}
+
+func TestRouterDescribe(t *testing.T) {
+	r := router.NewRouter()
+	r.Add(&router.Node{
+		ID:              "item.get",
+		Handler:         noop,
+		ValidationRules: map[string][]string{"id": {"required"}},
+	})
+
+	schema, err := r.Describe("item.get")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, schema["required"])
+
+	_, err = r.Describe("missing")
+	assert.True(t, errors.Is(err, constant.ErrNotFound))
+}
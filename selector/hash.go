@@ -0,0 +1,241 @@
+// file: mini/selector/hash.go
+package selector
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/rskv-p/mini/registry"
+)
+
+// ----------------------------------------------------
+// Consistent hash ring
+// ----------------------------------------------------
+
+// DefaultHashReplicas is the number of virtual nodes per real node.
+const DefaultHashReplicas = 100
+
+// HashRing maps keys to nodes using consistent hashing with virtual nodes.
+// Adding or removing a node only moves the keys owned by that node.
+type HashRing struct {
+	replicas int
+
+	mu     sync.RWMutex
+	hashes []uint32
+	owners map[uint32]string // virtual node -> node ID
+	nodes  map[string]*registry.Node
+	hits   map[string]int64
+}
+
+// NewHashRing creates an empty ring with the given virtual node count.
+func NewHashRing(replicas int) *HashRing {
+	if replicas <= 0 {
+		replicas = DefaultHashReplicas
+	}
+	return &HashRing{
+		replicas: replicas,
+		owners:   make(map[uint32]string),
+		nodes:    make(map[string]*registry.Node),
+		hits:     make(map[string]int64),
+	}
+}
+
+// Add places nodes on the ring. Existing node IDs are updated in place.
+func (r *HashRing) Add(nodes ...*registry.Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		if _, ok := r.nodes[n.ID]; !ok {
+			changed = true
+		}
+		r.nodes[n.ID] = n
+	}
+	if changed {
+		r.rebuild()
+	}
+}
+
+// Remove takes nodes off the ring by ID.
+func (r *HashRing) Remove(ids ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, id := range ids {
+		if _, ok := r.nodes[id]; ok {
+			delete(r.nodes, id)
+			delete(r.hits, id)
+			changed = true
+		}
+	}
+	if changed {
+		r.rebuild()
+	}
+}
+
+// Sync reconciles the ring with the given node set.
+func (r *HashRing) Sync(nodes []*registry.Node) {
+	want := make(map[string]*registry.Node, len(nodes))
+	for _, n := range nodes {
+		if n != nil {
+			want[n.ID] = n
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := len(want) != len(r.nodes)
+	for id := range r.nodes {
+		if _, ok := want[id]; !ok {
+			delete(r.hits, id)
+			changed = true
+		}
+	}
+	for id := range want {
+		if _, ok := r.nodes[id]; !ok {
+			changed = true
+		}
+	}
+	r.nodes = want
+	if changed {
+		r.rebuild()
+	}
+}
+
+// Get returns the node owning key.
+func (r *HashRing) Get(key string) (*registry.Node, error) {
+	return r.GetFunc(key, nil)
+}
+
+// GetFunc walks the ring clockwise from key and returns the first node
+// accepted by accept (nil accepts all). Rejected nodes stay on the ring, so
+// keys owned by accepted nodes keep their placement.
+func (r *HashRing) GetFunc(key string, accept func(*registry.Node) bool) (*registry.Node, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.hashes) == 0 {
+		return nil, ErrNoAvailableNodes
+	}
+	h := hashKey(key)
+	start := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	seen := make(map[string]bool)
+	for j := 0; j < len(r.hashes) && len(seen) < len(r.nodes); j++ {
+		id := r.owners[r.hashes[(start+j)%len(r.hashes)]]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		// Resolve through nodes so updates without an ID change are seen.
+		node := r.nodes[id]
+		if accept == nil || accept(node) {
+			r.hits[node.ID]++
+			return node, nil
+		}
+	}
+	return nil, ErrNoAvailableNodes
+}
+
+// Len returns the number of real nodes on the ring.
+func (r *HashRing) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.nodes)
+}
+
+// Distribution returns how many keys each node has served.
+func (r *HashRing) Distribution() map[string]int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]int64, len(r.nodes))
+	for id := range r.nodes {
+		out[id] = r.hits[id]
+	}
+	return out
+}
+
+// rebuild recomputes virtual node positions. Caller must hold the lock.
+func (r *HashRing) rebuild() {
+	r.hashes = r.hashes[:0]
+	r.owners = make(map[uint32]string, len(r.nodes)*r.replicas)
+	for id := range r.nodes {
+		for i := 0; i < r.replicas; i++ {
+			h := hashKey(strconv.Itoa(i) + "#" + id)
+			if _, taken := r.owners[h]; taken {
+				continue
+			}
+			r.owners[h] = id
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
+
+// ----------------------------------------------------
+// Selector integration (sticky selection by key)
+// ----------------------------------------------------
+
+// SelectByKey returns the node owning key for a service.
+// The same key keeps landing on the same node while the node set is stable.
+// The ring always spans every registered node; nodes rejected by filters or
+// health are skipped clockwise so other keys keep their owners.
+func (s *Selector) SelectByKey(service, key string, filters ...SelectorFilter) (*registry.Node, error) {
+	services, err := s.getCachedServices(service)
+	if err != nil {
+		return nil, err
+	}
+
+	all := collectNodes(services)
+	s.health.track(service, all)
+
+	ring := s.ring(service)
+	ring.Sync(all)
+	node, err := ring.GetFunc(key, func(n *registry.Node) bool {
+		return matchesAllFilters(n, filters) && s.health.admit(service, n.ID, false)
+	})
+	if err == nil {
+		return node, nil
+	}
+	// Health data never causes an outage: ignore it when every match is out.
+	return ring.GetFunc(key, func(n *registry.Node) bool { return matchesAllFilters(n, filters) })
+}
+
+// KeyDistribution returns per-node key counts for a service's hash ring.
+func (s *Selector) KeyDistribution(service string) map[string]int64 {
+	s.mu.RLock()
+	ring, ok := s.rings[service]
+	s.mu.RUnlock()
+	if !ok {
+		return map[string]int64{}
+	}
+	return ring.Distribution()
+}
+
+// ring returns (or lazily creates) the hash ring for a service.
+func (s *Selector) ring(service string) *HashRing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rings == nil {
+		s.rings = make(map[string]*HashRing)
+	}
+	r, ok := s.rings[service]
+	if !ok {
+		r = NewHashRing(s.opts.HashReplicas)
+		s.rings[service] = r
+	}
+	return r
+}
@@ -0,0 +1,172 @@
+// file: mini/selector/hash_test.go
+package selector_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/registry"
+	"github.com/rskv-p/mini/selector"
+	"github.com/stretchr/testify/assert"
+)
+
+func nodes(ids ...string) []*registry.Node {
+	out := make([]*registry.Node, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, &registry.Node{ID: id})
+	}
+	return out
+}
+
+func TestHashRing_Sticky(t *testing.T) {
+	r := selector.NewHashRing(0)
+	r.Add(nodes("a", "b", "c")...)
+	assert.Equal(t, 3, r.Len())
+
+	first, err := r.Get("user-42")
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		n, _ := r.Get("user-42")
+		assert.Equal(t, first.ID, n.ID)
+	}
+	assert.Equal(t, int64(11), r.Distribution()[first.ID])
+}
+
+func TestHashRing_Rebalance(t *testing.T) {
+	r := selector.NewHashRing(50)
+	r.Add(nodes("a", "b", "c")...)
+
+	before := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		n, _ := r.Get(key)
+		before[key] = n.ID
+	}
+
+	r.Remove("c")
+	for key, owner := range before {
+		n, _ := r.Get(key)
+		if owner != "c" {
+			assert.Equal(t, owner, n.ID, "key %s moved off a surviving node", key)
+		} else {
+			assert.NotEqual(t, "c", n.ID)
+		}
+	}
+	_, ok := r.Distribution()["c"]
+	assert.False(t, ok)
+}
+
+func TestHashRing_Empty(t *testing.T) {
+	r := selector.NewHashRing(10)
+	_, err := r.Get("x")
+	assert.ErrorIs(t, err, selector.ErrNoAvailableNodes)
+
+	r.Sync(nodes("a"))
+	n, err := r.Get("x")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", n.ID)
+
+	r.Sync(nil)
+	assert.Equal(t, 0, r.Len())
+}
+
+func TestSelector_SelectByKey(t *testing.T) {
+	mockReg := newMockRegistry()
+	mockReg.services["svc.k"] = []*registry.Service{{
+		Name: "svc.k",
+		Nodes: []*registry.Node{
+			{ID: "n1", Metadata: map[string]string{"zone": "eu"}},
+			{ID: "n2", Metadata: map[string]string{"zone": "us"}},
+			{ID: "n3", Metadata: map[string]string{"zone": "eu"}},
+		},
+	}}
+
+	sel := selector.NewSelector(mockReg, selector.SetCacheTTL(time.Hour), selector.SetHashReplicas(20))
+	assert.NoError(t, sel.Init())
+
+	a, err := sel.SelectByKey("svc.k", "user-1")
+	assert.NoError(t, err)
+	b, _ := sel.SelectByKey("svc.k", "user-1")
+	assert.Equal(t, a.ID, b.ID)
+
+	var total int64
+	for _, v := range sel.KeyDistribution("svc.k") {
+		total += v
+	}
+	assert.Equal(t, int64(2), total)
+
+	n, err := sel.SelectByKey("svc.k", "user-1", selector.MatchMeta("zone", "us"))
+	assert.NoError(t, err)
+	assert.Equal(t, "n2", n.ID)
+
+	_, err = sel.SelectByKey("svc.k", "user-1", selector.MatchMeta("zone", "xx"))
+	assert.Error(t, err)
+
+	assert.Empty(t, sel.KeyDistribution("svc.none"))
+}
+
+func TestHashRing_GetFunc(t *testing.T) {
+	r := selector.NewHashRing(20)
+	r.Add(nodes("a", "b", "c")...)
+
+	owner, _ := r.Get("user-7")
+	n, err := r.GetFunc("user-7", func(n *registry.Node) bool { return n.ID != owner.ID })
+	assert.NoError(t, err)
+	assert.NotEqual(t, owner.ID, n.ID)
+	assert.Equal(t, 3, r.Len())
+
+	_, err = r.GetFunc("user-7", func(*registry.Node) bool { return false })
+	assert.ErrorIs(t, err, selector.ErrNoAvailableNodes)
+}
+
+func TestHashRing_SyncUpdatesNodes(t *testing.T) {
+	r := selector.NewHashRing(20)
+	r.Sync([]*registry.Node{{ID: "a", Metadata: map[string]string{"zone": "x"}}})
+	r.Sync([]*registry.Node{{ID: "a", Metadata: map[string]string{"zone": "y"}}})
+
+	n, err := r.GetFunc("user-7", func(n *registry.Node) bool { return n.Metadata["zone"] == "y" })
+	assert.NoError(t, err)
+	assert.Equal(t, "y", n.Metadata["zone"])
+
+	r.Add(&registry.Node{ID: "a", Metadata: map[string]string{"zone": "z"}})
+	n, _ = r.Get("user-7")
+	assert.Equal(t, "z", n.Metadata["zone"])
+}
+
+func TestSelector_SelectByKeyFilterKeepsRing(t *testing.T) {
+	mockReg := newMockRegistry()
+	mockReg.services["svc.r"] = []*registry.Service{{
+		Name: "svc.r",
+		Nodes: []*registry.Node{
+			{ID: "n1", Metadata: map[string]string{"zone": "eu"}},
+			{ID: "n2", Metadata: map[string]string{"zone": "us"}},
+			{ID: "n3", Metadata: map[string]string{"zone": "eu"}},
+		},
+	}}
+	sel := selector.NewSelector(mockReg, selector.SetCacheTTL(time.Hour), selector.SetHashReplicas(20))
+	assert.NoError(t, sel.Init())
+
+	before := make(map[string]string)
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		n, err := sel.SelectByKey("svc.r", key)
+		assert.NoError(t, err)
+		before[key] = n.ID
+	}
+
+	// A filtered lookup must not shrink the shared ring or reset hits.
+	_, err := sel.SelectByKey("svc.r", "key-0", selector.MatchMeta("zone", "us"))
+	assert.NoError(t, err)
+	assert.Len(t, sel.KeyDistribution("svc.r"), 3)
+
+	for key, owner := range before {
+		n, _ := sel.SelectByKey("svc.r", key)
+		assert.Equal(t, owner, n.ID, "key %s moved", key)
+	}
+	var total int64
+	for _, v := range sel.KeyDistribution("svc.r") {
+		total += v
+	}
+	assert.Equal(t, int64(101), total)
+}
@@ -0,0 +1,255 @@
+// file: mini/selector/health.go
+package selector
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rskv-p/mini/registry"
+)
+
+// ----------------------------------------------------
+// Health feedback and outlier ejection
+// ----------------------------------------------------
+
+// healthAlpha weighs the newest sample in score and latency averages.
+const healthAlpha = 0.3
+
+// HealthOptions controls outlier detection driven by Report.
+type HealthOptions struct {
+	FailureThreshold  int           // consecutive failures before ejection
+	BaseEjection      time.Duration // first ejection; doubles on each repeat
+	MaxEjection       time.Duration // cap for ejection time
+	MaxEjectedPercent int           // never eject more than this share of nodes
+	RecoveryWindow    time.Duration // ramp-up after an ejection ends; <0 disables
+}
+
+// DefaultHealthOptions returns the outlier detection defaults.
+func DefaultHealthOptions() HealthOptions {
+	return HealthOptions{
+		FailureThreshold:  5,
+		BaseEjection:      30 * time.Second,
+		MaxEjection:       5 * time.Minute,
+		MaxEjectedPercent: 50,
+		RecoveryWindow:    30 * time.Second,
+	}
+}
+
+// NodeHealth is a snapshot of the feedback collected for one node.
+type NodeHealth struct {
+	NodeID              string        `json:"node_id"`
+	Score               float64       `json:"score"` // success rate average, 0..1
+	Successes           int64         `json:"successes"`
+	Failures            int64         `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	Latency             time.Duration `json:"latency"` // moving average
+	Ejected             bool          `json:"ejected"`
+	EjectedUntil        time.Time     `json:"ejected_until,omitempty"`
+	Ejections           int           `json:"ejections"`
+}
+
+type nodeState struct {
+	NodeHealth
+	recoverAt time.Time // end of the last ejection
+}
+
+// healthTracker keeps per-service node states.
+type healthTracker struct {
+	opts  HealthOptions
+	now   func() time.Time
+	mu    sync.Mutex
+	nodes map[string]map[string]*nodeState
+}
+
+func newHealthTracker(opts HealthOptions) *healthTracker {
+	def := DefaultHealthOptions()
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = def.FailureThreshold
+	}
+	if opts.BaseEjection <= 0 {
+		opts.BaseEjection = def.BaseEjection
+	}
+	if opts.MaxEjection < opts.BaseEjection {
+		opts.MaxEjection = max(def.MaxEjection, opts.BaseEjection)
+	}
+	if opts.MaxEjectedPercent <= 0 {
+		opts.MaxEjectedPercent = def.MaxEjectedPercent
+	}
+	if opts.RecoveryWindow == 0 {
+		opts.RecoveryWindow = def.RecoveryWindow
+	}
+	return &healthTracker{
+		opts:  opts,
+		now:   time.Now,
+		nodes: make(map[string]map[string]*nodeState),
+	}
+}
+
+func (h *healthTracker) state(service, nodeID string) *nodeState {
+	svc, ok := h.nodes[service]
+	if !ok {
+		svc = make(map[string]*nodeState)
+		h.nodes[service] = svc
+	}
+	st, ok := svc[nodeID]
+	if !ok {
+		st = &nodeState{NodeHealth: NodeHealth{NodeID: nodeID, Score: 1}}
+		svc[nodeID] = st
+	}
+	return st
+}
+
+func (h *healthTracker) report(service, nodeID string, err error, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	st := h.state(service, nodeID)
+	h.expire(st, now)
+
+	sample := 1.0
+	if err != nil {
+		sample = 0
+	}
+	st.Score = healthAlpha*sample + (1-healthAlpha)*st.Score
+	if latency > 0 {
+		if st.Latency == 0 {
+			st.Latency = latency
+		} else {
+			st.Latency = time.Duration(healthAlpha*float64(latency) + (1-healthAlpha)*float64(st.Latency))
+		}
+	}
+
+	if err == nil {
+		st.Successes++
+		st.ConsecutiveFailures = 0
+		return
+	}
+	st.Failures++
+	st.ConsecutiveFailures++
+	if !st.Ejected && st.ConsecutiveFailures >= h.opts.FailureThreshold && h.canEject(service) {
+		d := h.opts.BaseEjection << st.Ejections
+		if d <= 0 || d > h.opts.MaxEjection {
+			d = h.opts.MaxEjection
+		}
+		st.Ejected = true
+		st.EjectedUntil = now.Add(d)
+		st.Ejections++
+	}
+}
+
+// canEject enforces MaxEjectedPercent over the nodes seen for service.
+func (h *healthTracker) canEject(service string) bool {
+	svc := h.nodes[service]
+	ejected := 0
+	for _, st := range svc {
+		if st.Ejected {
+			ejected++
+		}
+	}
+	return (ejected+1)*100 <= len(svc)*h.opts.MaxEjectedPercent
+}
+
+// expire reinstates st once its ejection time has passed.
+func (h *healthTracker) expire(st *nodeState, now time.Time) {
+	if st.Ejected && !now.Before(st.EjectedUntil) {
+		st.Ejected = false
+		st.ConsecutiveFailures = 0
+		st.recoverAt = st.EjectedUntil
+		st.EjectedUntil = time.Time{}
+	}
+}
+
+// admit reports whether node may receive traffic. Ejected nodes are
+// skipped; recently reinstated nodes get a linearly growing share of picks.
+func (h *healthTracker) admit(service, nodeID string, ramp bool) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st, ok := h.nodes[service][nodeID]
+	if !ok {
+		return true
+	}
+	now := h.now()
+	h.expire(st, now)
+	if st.Ejected {
+		return false
+	}
+	if !ramp || h.opts.RecoveryWindow <= 0 || st.recoverAt.IsZero() {
+		return true
+	}
+	elapsed := now.Sub(st.recoverAt)
+	if elapsed >= h.opts.RecoveryWindow {
+		return true
+	}
+	return rand.Float64() < float64(elapsed)/float64(h.opts.RecoveryWindow)
+}
+
+// track reconciles the tracked nodes of service with the registered set.
+// New nodes start healthy so MaxEjectedPercent counts unreported nodes;
+// deregistered nodes are forgotten.
+func (h *healthTracker) track(service string, registered []*registry.Node) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(registered) == 0 {
+		delete(h.nodes, service)
+		return
+	}
+	keep := make(map[string]bool, len(registered))
+	for _, n := range registered {
+		keep[n.ID] = true
+		h.state(service, n.ID)
+	}
+	for id := range h.nodes[service] {
+		if !keep[id] {
+			delete(h.nodes[service], id)
+		}
+	}
+}
+
+// filter drops nodes that should not receive traffic, falling back to the
+// full set when nothing is left so health data never causes an outage.
+func (h *healthTracker) filter(service string, nodes []*registry.Node, ramp bool) []*registry.Node {
+	keep := make([]*registry.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if h.admit(service, n.ID, ramp) {
+			keep = append(keep, n)
+		}
+	}
+	if len(keep) == 0 {
+		return nodes
+	}
+	return keep
+}
+
+func (h *healthTracker) snapshot(service string) []NodeHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	out := make([]NodeHealth, 0, len(h.nodes[service]))
+	for _, st := range h.nodes[service] {
+		h.expire(st, now)
+		out = append(out, st.NodeHealth)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out
+}
+
+// ----------------------------------------------------
+// Selector API
+// ----------------------------------------------------
+
+// Report records the outcome of a call to nodeID of service. Transport
+// errors count as failures; latency feeds the moving average.
+func (s *Selector) Report(service, nodeID string, err error, latency time.Duration) {
+	s.health.report(service, nodeID, err, latency)
+}
+
+// NodeHealth returns the current health of every reported node of service.
+func (s *Selector) NodeHealth(service string) []NodeHealth {
+	return s.health.snapshot(service)
+}
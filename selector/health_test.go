@@ -0,0 +1,142 @@
+// file: mini/selector/health_test.go
+package selector_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/registry"
+	"github.com/rskv-p/mini/selector"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHealthSelector(t *testing.T, h selector.HealthOptions) selector.ISelector {
+	reg := newMockRegistry()
+	reg.services["svc"] = []*registry.Service{{
+		Name:  "svc",
+		Nodes: []*registry.Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+	}}
+	sel := selector.NewSelector(reg, selector.SetHealth(h), selector.SetStrategy(selector.Random))
+	assert.NoError(t, sel.Init())
+	return sel
+}
+
+func TestHealth_EjectAndReinstate(t *testing.T) {
+	sel := newHealthSelector(t, selector.HealthOptions{
+		FailureThreshold: 2,
+		BaseEjection:     30 * time.Millisecond,
+		RecoveryWindow:   -1,
+	})
+	boom := errors.New("boom")
+
+	sel.Report("svc", "a", nil, 10*time.Millisecond)
+	sel.Report("svc", "b", boom, 0)
+	sel.Report("svc", "b", boom, 0)
+
+	for i := 0; i < 20; i++ {
+		id, err := sel.Select("svc")
+		assert.NoError(t, err)
+		assert.NotEqual(t, "b", id)
+	}
+	node, err := sel.SelectByKey("svc", "any-key")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "b", node.ID)
+
+	health := sel.NodeHealth("svc")
+	assert.Len(t, health, 3)
+	assert.Equal(t, "a", health[0].NodeID)
+	assert.Equal(t, 10*time.Millisecond, health[0].Latency)
+	assert.True(t, health[1].Ejected)
+	assert.Equal(t, int64(2), health[1].Failures)
+	assert.Less(t, health[1].Score, health[0].Score)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.False(t, sel.NodeHealth("svc")[1].Ejected)
+
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		id, _ := sel.Select("svc")
+		seen[id] = true
+	}
+	assert.True(t, seen["b"])
+}
+
+func TestHealth_MaxEjectedPercent(t *testing.T) {
+	sel := newHealthSelector(t, selector.HealthOptions{
+		FailureThreshold:  1,
+		BaseEjection:      time.Minute,
+		MaxEjectedPercent: 34,
+	})
+	_, _ = sel.Select("svc") // registers candidates
+	boom := errors.New("boom")
+	sel.Report("svc", "a", boom, 0)
+	sel.Report("svc", "b", boom, 0)
+
+	health := sel.NodeHealth("svc")
+	assert.True(t, health[0].Ejected)
+	assert.False(t, health[1].Ejected)
+}
+
+func TestHealth_AllEjectedFallsBack(t *testing.T) {
+	reg := newMockRegistry()
+	reg.services["solo"] = []*registry.Service{{Name: "solo", Nodes: []*registry.Node{{ID: "x"}}}}
+	sel := selector.NewSelector(reg, selector.SetHealth(selector.HealthOptions{
+		FailureThreshold:  1,
+		MaxEjectedPercent: 100,
+	}))
+	assert.NoError(t, sel.Init())
+
+	sel.Report("solo", "x", errors.New("down"), 0)
+	assert.True(t, sel.NodeHealth("solo")[0].Ejected)
+
+	id, err := sel.Select("solo")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", id)
+}
+
+func TestHealth_PrunesDeregisteredNodes(t *testing.T) {
+	reg := newMockRegistry()
+	reg.services["svc"] = []*registry.Service{{
+		Name:  "svc",
+		Nodes: []*registry.Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+	}}
+	sel := selector.NewSelector(reg, selector.SetStrategy(selector.Random))
+	assert.NoError(t, sel.Init())
+
+	_, _ = sel.Select("svc")
+	sel.Report("svc", "c", errors.New("boom"), 0)
+	assert.Len(t, sel.NodeHealth("svc"), 3)
+
+	reg.services["svc"][0].Nodes = []*registry.Node{{ID: "a"}, {ID: "b"}}
+	sel.Invalidate("svc")
+	_, _ = sel.Select("svc")
+
+	health := sel.NodeHealth("svc")
+	assert.Len(t, health, 2)
+	assert.Equal(t, "a", health[0].NodeID)
+	assert.Equal(t, "b", health[1].NodeID)
+}
+
+func TestHealth_SelectByKeySkipsEjectedOnRing(t *testing.T) {
+	reg := newMockRegistry()
+	reg.services["svc"] = []*registry.Service{{
+		Name:  "svc",
+		Nodes: []*registry.Node{{ID: "a"}, {ID: "b"}, {ID: "c"}},
+	}}
+	sel := selector.NewSelector(reg, selector.SetHealth(selector.HealthOptions{
+		FailureThreshold: 1,
+		BaseEjection:     time.Minute,
+		RecoveryWindow:   -1,
+	}))
+	assert.NoError(t, sel.Init())
+
+	owner, err := sel.SelectByKey("svc", "user-1")
+	assert.NoError(t, err)
+	sel.Report("svc", owner.ID, errors.New("boom"), 0)
+
+	next, err := sel.SelectByKey("svc", "user-1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, owner.ID, next.ID)
+	assert.Len(t, sel.KeyDistribution("svc"), 3)
+}
@@ -24,8 +24,12 @@ type ISelector interface {
 	Init() error
 	Select(service string, filters ...SelectorFilter) (string, error)
 	SelectNode(service string, filters ...SelectorFilter) (*registry.Node, error)
+	SelectByKey(service, key string, filters ...SelectorFilter) (*registry.Node, error)
+	KeyDistribution(service string) map[string]int64
 	Invalidate(service string)
 	DumpCache() map[string][]string
+	Report(service, nodeID string, err error, latency time.Duration)
+	NodeHealth(service string) []NodeHealth
 }
 
 // Strategy defines how to pick nodes from services.
@@ -73,6 +77,8 @@ func NewSelector(reg registry.IRegistry, opts ...Option) ISelector {
 		registry: reg,
 		opts:     sOpts,
 		cache:    make(map[string]cachedServices),
+		rings:    make(map[string]*HashRing),
+		health:   newHealthTracker(sOpts.Health),
 	}
 }
 
@@ -83,6 +89,9 @@ type Selector struct {
 
 	mu    sync.RWMutex
 	cache map[string]cachedServices
+	rings map[string]*HashRing
+
+	health *healthTracker
 }
 
 // Init ensures registry and strategy are set.
@@ -118,6 +127,8 @@ func (s *Selector) SelectNode(service string, filters ...SelectorFilter) (*regis
 		return nil, fmt.Errorf("selector: service %q not found", service)
 	}
 
+	s.health.track(service, collectNodes(services))
+
 	// apply filters to nodes
 	var filtered []*registry.Service
 	for _, svc := range services {
@@ -130,6 +141,7 @@ func (s *Selector) SelectNode(service string, filters ...SelectorFilter) (*regis
 				keep = append(keep, n)
 			}
 		}
+		keep = s.health.filter(service, keep, true)
 		if len(keep) > 0 {
 			filtered = append(filtered, &registry.Service{Name: svc.Name, Nodes: keep})
 		}
@@ -14,6 +14,8 @@ type Options struct {
 	Strategy     Strategy      // Node selection strategy function
 	StrategyName string        // Human-readable name of strategy
 	CacheTTL     time.Duration // TTL for cached service registry entries
+	HashReplicas int           // Virtual nodes per node for SelectByKey
+	Health       HealthOptions // Outlier ejection driven by Report
 }
 
 // Option applies configuration changes to Options.
@@ -41,11 +43,27 @@ func SetCacheTTL(ttl time.Duration) Option {
 	}
 }
 
+// SetHashReplicas sets the virtual node count used by SelectByKey rings.
+func SetHashReplicas(n int) Option {
+	return func(o *Options) {
+		o.HashReplicas = n
+	}
+}
+
+// SetHealth configures outlier detection; zero fields keep their defaults.
+func SetHealth(h HealthOptions) Option {
+	return func(o *Options) {
+		o.Health = h
+	}
+}
+
 // WithDefaults returns safe default options.
 func WithDefaults() Options {
 	return Options{
 		Strategy:     RoundRobin,
 		StrategyName: "round_robin",
 		CacheTTL:     2 * time.Second,
+		HashReplicas: DefaultHashReplicas,
+		Health:       DefaultHealthOptions(),
 	}
 }
@@ -4,10 +4,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/google/uuid"
@@ -42,7 +44,9 @@ type IClient interface {
 	Context() context.Context
 
 	Pub(service string, msg codec.IMessage) error
+	PubWithContext(ctx context.Context, service string, msg codec.IMessage) error
 	Req(service string, msg codec.IMessage, handler transport.ResponseHandler) error
+	ReqWithContext(ctx context.Context, service string, msg codec.IMessage, handler transport.ResponseHandler) error
 	Respond(msg codec.IMessage, subject string) error
 
 	SubscribeTopic(topic string, handler transport.MsgHandler) error
@@ -81,15 +85,31 @@ type Service struct {
 	wg     sync.WaitGroup
 	mu     sync.RWMutex
 
+	httpSrv    *http.Server
+	registered atomic.Bool
+
 	actions     map[string]actionInfo
 	middlewares []Middleware
 	metrics     map[string]int64
+
+	requestLogging bool             // RequestLogging middleware installed
+	chaos          *transport.Chaos // set once by setupChaos
 }
 
 func NewService(name, version string, extra ...Option) *Service {
-	cfg, err := config.New(config.FromEnv("SRV_"))
-	if err != nil {
-		panic(fmt.Sprintf("load config: %v", err))
+	// The config is needed before the defaults below are built, so look
+	// for WithConfig first.
+	var pre Options
+	for _, o := range extra {
+		o(&pre)
+	}
+	cfg := pre.Config
+	if cfg == nil {
+		envCfg, err := config.New(config.FromEnv("SRV_"))
+		if err != nil {
+			panic(fmt.Sprintf("load config: %v", err))
+		}
+		cfg = envCfg
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -109,13 +129,15 @@ func NewService(name, version string, extra ...Option) *Service {
 	}
 
 	defaults := []Option{
-		Logger(logger.NewLogger(name, cfg.MustString("log_level"))),
+		Logger(logger.NewLogger(name, cfg.MustString("log_level"),
+			logger.WithComponentLevels(logger.ParseLevels(cfg.MustString("log_levels"))))),
 		Transport(transport.New(transport.Subject(subject))),
 		Registry(registry.NewRegistry()),
 		Selector(selector.NewSelector(registry.NewRegistry(), selector.SetStrategy(selector.RoundRobin))),
 	}
 
 	s.opts = newOptions(append(defaults, extra...)...)
+	s.opts.Config = cfg
 
 	if s.opts.Logger != nil {
 		s.logger = s.opts.Logger
@@ -141,6 +163,7 @@ func (s *Service) Config() map[string]string {
 	for _, k := range keys {
 		out[k] = s.config.MustString(k)
 	}
+	out["profile"] = s.config.Profile()
 	return out
 }
 
@@ -164,7 +187,7 @@ func (s *Service) Init(opts ...Option) error {
 		}
 		s.wg.Add(1)
 		defer s.wg.Done()
-		s.ServerHandler(msg)
+		s.serveMessage(context.WithValue(context.Background(), PayloadSizeKey, len(data)), msg)
 		return nil
 	})
 
@@ -179,11 +202,28 @@ func (s *Service) Init(opts ...Option) error {
 		s.opts.Router = router.NewRouter(router.Name(s.name + "/" + s.version))
 	}
 
+	s.RegisterAction(ConfigInfoAction, nil, s.configInfoAction)
+	if s.opts.ConfigReload {
+		s.RegisterAction(ConfigReloadAction, nil, s.reloadAction)
+	}
+	if s.opts.Describe {
+		s.RegisterAction(DescribeAction, []InputSchemaField{
+			{Name: "id", Type: TypeString, Required: true},
+		}, s.describeAction)
+	}
+
+	if s.opts.RequestLogging != nil && !s.requestLogging {
+		s.requestLogging = true
+		s.middlewares = append([]Middleware{RequestLogging(s.logger, *s.opts.RequestLogging)}, s.middlewares...)
+	}
+
+	s.setupChaos()
+
+	// prepareHandler applies the middleware chain itself.
 	for name, info := range s.actions {
-		wrapped := chainMiddlewares(info.handler, s.middlewares...)
 		s.opts.Router.Add(&router.Node{
 			ID:      name,
-			Handler: s.prepareHandler(wrapped),
+			Handler: s.prepareHandler(info.handler),
 		})
 	}
 
@@ -203,6 +243,22 @@ func (s *Service) Run() error {
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	if s.opts.ConfigReload {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		defer signal.Stop(hupCh)
+		go func() {
+			for {
+				select {
+				case <-hupCh:
+					_, _ = s.ReloadConfig()
+				case <-s.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
 	<-sigCh
 
 	s.logger.Info("⏹ stopping %s %s", s.name, s.version)
@@ -216,17 +272,31 @@ func (s *Service) Stop() error {
 	}
 
 	s.wg.Wait()
+	if err := s.stopHTTP(); err != nil {
+		s.logger.Warn("http shutdown: %v", err)
+	}
 	if err := s.deregister(); err != nil {
 		return err
 	}
 	return s.opts.Transport.Close()
 }
 
+// start registers, subscribes and starts the HTTP listener. A failed step
+// undoes the ones before it so the node is not left half-registered.
 func (s *Service) start() error {
 	if err := s.register(); err != nil {
 		return err
 	}
-	return s.opts.Transport.Subscribe()
+	if err := s.opts.Transport.Subscribe(); err != nil {
+		_ = s.deregister()
+		return err
+	}
+	if err := s.startHTTP(); err != nil {
+		_ = s.opts.Transport.Unsubscribe()
+		_ = s.deregister()
+		return err
+	}
+	return nil
 }
 
 func (s *Service) register() error {
@@ -239,7 +309,11 @@ func (s *Service) register() error {
 			return err
 		}
 	}
-	return s.opts.Registry.Register(svc)
+	if err := s.opts.Registry.Register(svc); err != nil {
+		return err
+	}
+	s.registered.Store(true)
+	return nil
 }
 
 func (s *Service) deregister() error {
@@ -247,6 +321,7 @@ func (s *Service) deregister() error {
 		Name:  s.name,
 		Nodes: []*registry.Node{{ID: s.id}},
 	}
+	s.registered.Store(false)
 	if err := s.opts.Registry.Deregister(svc); err != nil {
 		return err
 	}
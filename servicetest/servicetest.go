@@ -0,0 +1,136 @@
+// file: mini/servicetest/servicetest.go
+// Package servicetest provides an in-memory harness for unit testing
+// service actions and middlewares without a running NSQ.
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	service "github.com/rskv-p/mini"
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+)
+
+// DefaultTimeout bounds how long Invoke waits for a response.
+const DefaultTimeout = 2 * time.Second
+
+// Harness wires a Service to a MockTransport.
+type Harness struct {
+	Service   *service.Service
+	Transport *MockTransport
+	Timeout   time.Duration
+
+	seq atomic.Int64
+}
+
+// New creates a service backed by a MockTransport. Register actions and
+// middlewares on h.Service, then call Start.
+func New(name string, opts ...service.Option) *Harness {
+	mt := NewMockTransport()
+	opts = append(opts, service.Transport(mt))
+	return &Harness{
+		Service:   service.NewService(name, "test", opts...),
+		Transport: mt,
+		Timeout:   DefaultTimeout,
+	}
+}
+
+// Start initializes the service so registered actions become routable.
+func (h *Harness) Start() error {
+	return h.Service.Init()
+}
+
+// Invoke sends a request for action and waits for the response.
+func (h *Harness) Invoke(action string, input map[string]any) (*Response, error) {
+	n := h.seq.Add(1)
+	replyTo := fmt.Sprintf("servicetest.reply.%d", n)
+
+	req := codec.NewRequest(action, fmt.Sprintf("servicetest-%d", n))
+	req.SetReplyTo(replyTo)
+	for k, v := range input {
+		req.Set(k, v)
+	}
+	if err := h.Transport.DeliverMessage(req); err != nil {
+		return nil, err
+	}
+
+	rec, err := h.Transport.Wait(replyTo, h.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := rec.Message()
+	if err != nil {
+		return nil, err
+	}
+	return &Response{IMessage: msg}, nil
+}
+
+// Publish delivers a one-way message for action.
+func (h *Harness) Publish(action string, input map[string]any) error {
+	msg := codec.NewMessage(constant.MessageTypePublish)
+	msg.SetNode(action)
+	for k, v := range input {
+		msg.Set(k, v)
+	}
+	return h.Transport.DeliverMessage(msg)
+}
+
+// ----------------------------------------------------
+// Response recorder
+// ----------------------------------------------------
+
+// Response is a decoded action response.
+type Response struct {
+	codec.IMessage
+}
+
+// Status returns the response status code.
+func (r *Response) Status() int {
+	if m, ok := r.IMessage.(*codec.Message); ok {
+		return m.StatusCode
+	}
+	return 0
+}
+
+// Result decodes the action result into target.
+func (r *Response) Result(target any) error {
+	return r.GetResult(target)
+}
+
+// ----------------------------------------------------
+// Direct invocation
+// ----------------------------------------------------
+
+// CallAction runs fn wrapped with mws, bypassing routing and transport.
+func CallAction(fn service.ActionFunc, input map[string]any, mws ...service.Middleware) (any, error) {
+	for i := len(mws) - 1; i >= 0; i-- {
+		fn = mws[i](fn)
+	}
+	return fn(context.Background(), input)
+}
+
+// MiddlewareCall captures what a middleware did around a stub action.
+type MiddlewareCall struct {
+	Called bool           // next was invoked
+	Input  map[string]any // input as seen by next
+	Ctx    context.Context
+	Result any // result returned by the middleware
+	Err    error
+}
+
+// RunMiddleware wraps a stub action returning (result, err) with mw, calls
+// it with input and records the outcome.
+func RunMiddleware(mw service.Middleware, ctx context.Context, input map[string]any, result any, err error) MiddlewareCall {
+	var call MiddlewareCall
+	next := func(ctx context.Context, in map[string]any) (any, error) {
+		call.Called = true
+		call.Input = in
+		call.Ctx = ctx
+		return result, err
+	}
+	call.Result, call.Err = mw(next)(ctx, input)
+	return call
+}
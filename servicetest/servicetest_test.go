@@ -0,0 +1,92 @@
+// file: mini/servicetest/servicetest_test.go
+package servicetest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	service "github.com/rskv-p/mini"
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/servicetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHarness(t *testing.T) *servicetest.Harness {
+	h := servicetest.New("svctest")
+	h.Service.RegisterAction("greet", []service.InputSchemaField{
+		{Name: "name", Type: service.TypeString, Required: true},
+	}, func(ctx context.Context, input map[string]any) (any, error) {
+		return map[string]any{"hello": input["name"]}, nil
+	})
+	assert.NoError(t, h.Start())
+	return h
+}
+
+func TestHarness_Invoke(t *testing.T) {
+	h := newHarness(t)
+
+	resp, err := h.Invoke("greet", map[string]any{"name": "bob"})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.Status())
+
+	var out map[string]string
+	assert.NoError(t, resp.Result(&out))
+	assert.Equal(t, "bob", out["hello"])
+
+	resp, err = h.Invoke("greet", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Status())
+	assert.True(t, resp.HasError())
+
+	resp, err = h.Invoke("missing", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Status())
+}
+
+func TestMockTransport_RequestStub(t *testing.T) {
+	mt := servicetest.NewMockTransport()
+	mt.OnRequest("users", func(req codec.IMessage) (codec.IMessage, error) {
+		resp := codec.NewResponse(req.GetContextID(), 200)
+		resp.SetResult(req.GetString("id"))
+		return resp, nil
+	})
+
+	req := codec.NewRequest("users.get", "ctx-1")
+	req.Set("id", "42")
+	data, _ := codec.Marshal(req)
+
+	var got string
+	assert.NoError(t, mt.Request("users", data, func(m codec.IMessage) error {
+		return m.GetResult(&got)
+	}))
+	assert.Equal(t, "42", got)
+	assert.Len(t, mt.Published("users"), 1)
+
+	assert.ErrorIs(t, mt.Request("orders", data, nil), servicetest.ErrNoStub)
+}
+
+func TestRunMiddleware(t *testing.T) {
+	deny := func(next service.ActionFunc) service.ActionFunc {
+		return func(ctx context.Context, input map[string]any) (any, error) {
+			if input["token"] == nil {
+				return nil, errors.New("unauthorized")
+			}
+			return next(ctx, input)
+		}
+	}
+
+	call := servicetest.RunMiddleware(deny, context.Background(), map[string]any{}, "ok", nil)
+	assert.False(t, call.Called)
+	assert.EqualError(t, call.Err, "unauthorized")
+
+	call = servicetest.RunMiddleware(deny, context.Background(), map[string]any{"token": "t"}, "ok", nil)
+	assert.True(t, call.Called)
+	assert.Equal(t, "ok", call.Result)
+
+	out, err := servicetest.CallAction(func(ctx context.Context, in map[string]any) (any, error) {
+		return in["x"], nil
+	}, map[string]any{"x": 1, "token": "t"}, deny)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, out)
+}
@@ -0,0 +1,322 @@
+// file: mini/servicetest/transport.go
+package servicetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/transport"
+)
+
+var (
+	ErrNoStub  = errors.New("servicetest: no request stub for subject")
+	ErrTimeout = errors.New("servicetest: timed out waiting for message")
+)
+
+// Record is a single message published through the mock transport.
+type Record struct {
+	Subject string
+	Data    []byte
+}
+
+// Message decodes the recorded payload.
+func (r Record) Message() (codec.IMessage, error) {
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(r.Data, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RequestStub answers a request sent through the mock transport.
+type RequestStub func(req codec.IMessage) (codec.IMessage, error)
+
+// MockTransport is an in-memory transport.ITransport. Published messages are
+// recorded, requests are answered by stubs and Deliver feeds the service
+// handler directly.
+type MockTransport struct {
+	mu          sync.Mutex
+	opts        transport.Options
+	handler     transport.MsgHandler
+	middlewares []transport.MiddlewareFunc
+	records     []Record
+	stubs       map[string]RequestStub
+	topics      map[string]transport.MsgHandler
+	prefixes    map[string]transport.MsgHandler
+	notify      chan struct{}
+	subscribed  bool
+	closed      bool
+}
+
+var _ transport.ITransport = (*MockTransport)(nil)
+
+// NewMockTransport returns an empty mock transport.
+func NewMockTransport() *MockTransport {
+	return &MockTransport{
+		opts:     transport.Options{Subject: "servicetest"},
+		stubs:    make(map[string]RequestStub),
+		topics:   make(map[string]transport.MsgHandler),
+		prefixes: make(map[string]transport.MsgHandler),
+		notify:   make(chan struct{}),
+	}
+}
+
+// ----------------------------------------------------
+// Test helpers
+// ----------------------------------------------------
+
+// OnRequest registers a stub answering requests to subject.
+func (m *MockTransport) OnRequest(subject string, stub RequestStub) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stubs[subject] = stub
+}
+
+// Deliver passes data to the handler set by the service, as if it had
+// arrived on the service subject.
+func (m *MockTransport) Deliver(data []byte) error {
+	m.mu.Lock()
+	h := m.handler
+	mws := m.middlewares
+	m.mu.Unlock()
+	if h == nil {
+		return errors.New("servicetest: no handler set")
+	}
+
+	var th transport.TransportHandler = func(_ context.Context, _ string, data []byte) error {
+		return h(data)
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		th = mws[i](th)
+	}
+	return th(context.Background(), m.opts.Subject, data)
+}
+
+// DeliverMessage marshals msg and delivers it.
+func (m *MockTransport) DeliverMessage(msg codec.IMessage) error {
+	data, err := codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return m.Deliver(data)
+}
+
+// DeliverTopic invokes the handler subscribed to topic or a matching prefix.
+func (m *MockTransport) DeliverTopic(topic string, data []byte) error {
+	m.mu.Lock()
+	h, ok := m.topics[topic]
+	if !ok {
+		for p, ph := range m.prefixes {
+			if strings.HasPrefix(topic, p) {
+				h, ok = ph, true
+				break
+			}
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("servicetest: no subscription for %s", topic)
+	}
+	return h(data)
+}
+
+// Published returns all records, optionally filtered by subject.
+func (m *MockTransport) Published(subject ...string) []Record {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Record
+	for _, r := range m.records {
+		if len(subject) == 0 || r.Subject == subject[0] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Wait blocks until a message is published to subject or timeout elapses.
+func (m *MockTransport) Wait(subject string, timeout time.Duration) (Record, error) {
+	deadline := time.After(timeout)
+	for {
+		m.mu.Lock()
+		for _, r := range m.records {
+			if r.Subject == subject {
+				m.mu.Unlock()
+				return r, nil
+			}
+		}
+		ch := m.notify
+		m.mu.Unlock()
+
+		select {
+		case <-ch:
+		case <-deadline:
+			return Record{}, fmt.Errorf("%w: %s", ErrTimeout, subject)
+		}
+	}
+}
+
+// Reset clears recorded messages.
+func (m *MockTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = nil
+}
+
+// Subscribed reports whether Subscribe was called and not undone.
+func (m *MockTransport) Subscribed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.subscribed
+}
+
+func (m *MockTransport) record(subject string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, Record{Subject: subject, Data: append([]byte(nil), data...)})
+	close(m.notify)
+	m.notify = make(chan struct{})
+}
+
+// ----------------------------------------------------
+// transport.ITransport
+// ----------------------------------------------------
+
+func (m *MockTransport) Init() error { return nil }
+
+func (m *MockTransport) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.subscribed = false
+	return nil
+}
+
+func (m *MockTransport) Options() transport.Options { return m.opts }
+
+func (m *MockTransport) SetHandler(h transport.MsgHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handler = h
+}
+
+func (m *MockTransport) Use(mw transport.MiddlewareFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.middlewares = append(m.middlewares, mw)
+}
+
+func (m *MockTransport) Subscribe() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribed = true
+	return nil
+}
+
+func (m *MockTransport) Unsubscribe() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribed = false
+	return nil
+}
+
+func (m *MockTransport) IsConnected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.closed
+}
+
+func (m *MockTransport) Ping() error { return m.Health() }
+func (m *MockTransport) Health() error {
+	if !m.IsConnected() {
+		return transport.ErrDisconnected
+	}
+	return nil
+}
+
+func (m *MockTransport) Request(subject string, req []byte, handler transport.ResponseHandler) error {
+	return m.RequestWithContext(context.Background(), subject, req, handler)
+}
+
+func (m *MockTransport) RequestWithContext(ctx context.Context, subject string, req []byte, handler transport.ResponseHandler) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.record(subject, req)
+
+	m.mu.Lock()
+	stub, ok := m.stubs[subject]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoStub, subject)
+	}
+
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(req, msg); err != nil {
+		return err
+	}
+	resp, err := stub(msg)
+	if err != nil {
+		return err
+	}
+	if handler != nil {
+		return handler(resp)
+	}
+	return nil
+}
+
+func (m *MockTransport) Publish(subject string, data []byte) error {
+	if !m.IsConnected() {
+		return transport.ErrDisconnected
+	}
+	m.record(subject, data)
+	return nil
+}
+
+func (m *MockTransport) Respond(replyTo string, msg codec.IMessage) error {
+	data, err := codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return m.Publish(replyTo, data)
+}
+
+func (m *MockTransport) SendFile(msg codec.IMessage, subject string, file []byte, _ int) error {
+	return m.Publish(subject, file)
+}
+
+func (m *MockTransport) Broadcast(subjects []string, data []byte) error {
+	for _, s := range subjects {
+		if err := m.Publish(s, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockTransport) Broadcastf(format string, keys ...string) error {
+	for _, k := range keys {
+		if err := m.Publish(fmt.Sprintf(format, k), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockTransport) SubscribeTopic(topic string, handler transport.MsgHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.topics[topic] = handler
+	return nil
+}
+
+func (m *MockTransport) SubscribePrefix(prefix string, handler transport.MsgHandler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prefixes[prefix] = handler
+	return nil
+}
@@ -0,0 +1,99 @@
+// file: mini/storage/actions.go
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	service "github.com/rskv-p/mini"
+	"github.com/rskv-p/mini/router"
+)
+
+const (
+	ActionPut    = "store.put"
+	ActionGet    = "store.get"
+	ActionDelete = "store.delete"
+	ActionList   = "store.list"
+)
+
+// RegisterActions exposes the store as store.put/get/delete/list actions.
+// File data is passed base64-encoded in the "data" field.
+func (s *Store) RegisterActions(svc *service.Service) {
+	svc.RegisterAction(ActionPut, []service.InputSchemaField{
+		{Name: "key", Type: service.TypeString, Required: true},
+		{Name: "data", Type: service.TypeString, Required: true},
+		{Name: "filename", Type: service.TypeString},
+		{Name: "mime", Type: service.TypeString},
+		{Name: "owner", Type: service.TypeString},
+	}, s.putAction)
+
+	svc.RegisterAction(ActionGet, []service.InputSchemaField{
+		{Name: "key", Type: service.TypeString, Required: true},
+	}, s.getAction)
+
+	svc.RegisterAction(ActionDelete, []service.InputSchemaField{
+		{Name: "key", Type: service.TypeString, Required: true},
+	}, s.deleteAction)
+
+	svc.RegisterAction(ActionList, []service.InputSchemaField{
+		{Name: "prefix", Type: service.TypeString, Default: ""},
+	}, s.listAction)
+}
+
+func (s *Store) putAction(ctx context.Context, input map[string]any) (any, error) {
+	data, err := base64.StdEncoding.DecodeString(str(input, "data"))
+	if err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	obj, err := s.PutBytes(ctx, str(input, "key"), data, Object{
+		Filename: str(input, "filename"),
+		Mime:     str(input, "mime"),
+		Owner:    str(input, "owner"),
+	})
+	if err != nil {
+		return nil, actionError(err)
+	}
+	return obj, nil
+}
+
+func (s *Store) getAction(ctx context.Context, input map[string]any) (any, error) {
+	data, obj, err := s.GetBytes(ctx, str(input, "key"))
+	if err != nil {
+		return nil, actionError(err)
+	}
+	return map[string]any{"object": obj, "data": data}, nil
+}
+
+func (s *Store) deleteAction(ctx context.Context, input map[string]any) (any, error) {
+	key := str(input, "key")
+	if err := s.Delete(ctx, key); err != nil {
+		return nil, actionError(err)
+	}
+	return map[string]any{"deleted": key}, nil
+}
+
+func (s *Store) listAction(ctx context.Context, input map[string]any) (any, error) {
+	objs, err := s.List(ctx, str(input, "prefix"))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"objects": objs}, nil
+}
+
+// actionError maps backend errors to response statuses.
+func actionError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return &router.Error{StatusCode: 404, Message: err.Error()}
+	case errors.Is(err, ErrInvalidKey):
+		return &router.Error{StatusCode: 400, Message: err.Error()}
+	}
+	return err
+}
+
+func str(input map[string]any, key string) string {
+	s, _ := input[key].(string)
+	return s
+}
@@ -0,0 +1,59 @@
+// file: mini/storage/actions_test.go
+package storage_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/rskv-p/mini/servicetest"
+	"github.com/rskv-p/mini/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreActions(t *testing.T) {
+	st := newStore(t)
+	h := servicetest.New("store")
+	st.RegisterActions(h.Service)
+	assert.NoError(t, h.Start())
+
+	resp, err := h.Invoke(storage.ActionPut, map[string]any{
+		"key":  "a.txt",
+		"data": base64.StdEncoding.EncodeToString([]byte("hi")),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.Status())
+
+	resp, err = h.Invoke(storage.ActionGet, map[string]any{"key": "a.txt"})
+	assert.NoError(t, err)
+	var got struct {
+		Object storage.Object `json:"object"`
+		Data   []byte         `json:"data"`
+	}
+	assert.NoError(t, resp.Result(&got))
+	assert.Equal(t, "hi", string(got.Data))
+	assert.Equal(t, int64(2), got.Object.Size)
+
+	resp, err = h.Invoke(storage.ActionList, nil)
+	assert.NoError(t, err)
+	var list struct {
+		Objects []storage.Object `json:"objects"`
+	}
+	assert.NoError(t, resp.Result(&list))
+	assert.Len(t, list.Objects, 1)
+
+	resp, err = h.Invoke(storage.ActionDelete, map[string]any{"key": "a.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.Status())
+
+	resp, err = h.Invoke(storage.ActionGet, map[string]any{"key": "a.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Status())
+
+	resp, err = h.Invoke(storage.ActionDelete, map[string]any{"key": "a.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.Status())
+
+	resp, err = h.Invoke(storage.ActionGet, map[string]any{"key": "../x"})
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.Status())
+}
@@ -0,0 +1,178 @@
+// file: mini/storage/local.go
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const metaSuffix = ".meta.json"
+
+var _ IBackend = (*LocalBackend)(nil)
+
+// LocalBackend stores objects as files under a root directory, with
+// metadata in a "<key>.meta.json" sidecar.
+type LocalBackend struct {
+	root string
+}
+
+// NewLocal returns a disk backend rooted at dir, creating it if needed.
+func NewLocal(dir string) (*LocalBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalBackend{root: dir}, nil
+}
+
+// Root returns the storage directory.
+func (b *LocalBackend) Root() string { return b.root }
+
+func (b *LocalBackend) Put(ctx context.Context, key string, r io.Reader, meta Object) (Object, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return Object{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Object{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Object{}, err
+	}
+
+	// Write to a temp file so readers never see a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	meta.Key = key
+	meta.Size = n
+	meta.Checksum = hex.EncodeToString(h.Sum(nil))
+	meta.CreatedAt = time.Now().UTC()
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return Object{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Object{}, err
+	}
+	if err := os.WriteFile(path+metaSuffix, data, 0644); err != nil {
+		return Object{}, err
+	}
+	return meta, nil
+}
+
+func (b *LocalBackend) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	obj, err := b.Stat(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	path, _ := b.path(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Object{}, mapNotExist(err)
+	}
+	return f, obj, nil
+}
+
+func (b *LocalBackend) Stat(_ context.Context, key string) (Object, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return Object{}, err
+	}
+	data, err := os.ReadFile(path + metaSuffix)
+	if err != nil {
+		return Object{}, mapNotExist(err)
+	}
+	var obj Object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return Object{}, err
+	}
+	return obj, nil
+}
+
+func (b *LocalBackend) Delete(_ context.Context, key string) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return mapNotExist(err)
+	}
+	if err := os.Remove(path + metaSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns objects whose key starts with prefix, sorted by key.
+func (b *LocalBackend) List(ctx context.Context, prefix string) ([]Object, error) {
+	var out []Object
+	err := filepath.WalkDir(b.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, metaSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(b.root, strings.TrimSuffix(path, metaSuffix))
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		obj, err := b.Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		out = append(out, obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// path maps a key to a file path, rejecting keys that escape the root.
+func (b *LocalBackend) path(key string) (string, error) {
+	if key == "" || strings.HasSuffix(key, metaSuffix) {
+		return "", ErrInvalidKey
+	}
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(b.root, clean), nil
+}
+
+func mapNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
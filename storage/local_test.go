@@ -0,0 +1,84 @@
+// file: mini/storage/local_test.go
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStore(t *testing.T) *storage.Store {
+	b, err := storage.NewLocal(t.TempDir())
+	assert.NoError(t, err)
+	return storage.New(b)
+}
+
+func TestLocal_PutGetDelete(t *testing.T) {
+	st := newStore(t)
+	ctx := context.Background()
+
+	obj, err := st.PutBytes(ctx, "docs/a.txt", []byte("hello"), storage.Object{Filename: "a.txt", Mime: "text/plain"})
+	assert.NoError(t, err)
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), obj.Checksum)
+	assert.Equal(t, int64(5), obj.Size)
+
+	data, got, err := st.GetBytes(ctx, "docs/a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, "text/plain", got.Mime)
+
+	_, _ = st.PutBytes(ctx, "docs/b.txt", []byte("b"), storage.Object{})
+	_, _ = st.PutBytes(ctx, "img/c.png", []byte("c"), storage.Object{})
+	list, err := st.List(ctx, "docs/")
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "docs/a.txt", list[0].Key)
+
+	assert.NoError(t, st.Delete(ctx, "docs/a.txt"))
+	_, err = st.Stat(ctx, "docs/a.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, st.Delete(ctx, "docs/a.txt"), storage.ErrNotFound)
+}
+
+func TestLocal_InvalidKey(t *testing.T) {
+	st := newStore(t)
+	for _, key := range []string{"", "../escape", "/abs", "x.meta.json"} {
+		_, err := st.PutBytes(context.Background(), key, nil, storage.Object{})
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
+
+func TestStore_ReceiveFiles(t *testing.T) {
+	st := newStore(t)
+	var stored storage.Object
+	h := st.ReceiveFiles("alice", func(obj storage.Object, err error) {
+		assert.NoError(t, err)
+		stored = obj
+	})
+
+	for i, part := range []string{"he", "llo"} {
+		msg := codec.NewMessage(constant.MessageTypeStream)
+		msg.Set("fileID", "f1")
+		msg.Set("chunkIndex", i)
+		msg.Set("chunkTotal", 2)
+		msg.Set("isLast", i == 1)
+		msg.Set("filename", "greeting.txt")
+		msg.Set("fileChunk", []byte(part))
+		data, _ := codec.Marshal(msg)
+		assert.NoError(t, h(data))
+	}
+
+	assert.Equal(t, "f1", stored.Key)
+	assert.Equal(t, "alice", stored.Owner)
+	data, obj, err := st.GetBytes(context.Background(), "f1")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, "greeting.txt", obj.Filename)
+}
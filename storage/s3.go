@@ -0,0 +1,371 @@
+// file: mini/storage/s3.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ IBackend = (*S3Backend)(nil)
+
+// S3Config configures an S3-compatible backend (AWS S3, MinIO, R2, ...).
+type S3Config struct {
+	Endpoint  string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://localhost:9000"
+	Region    string // defaults to "us-east-1"
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Client    *http.Client // defaults to http.DefaultClient
+}
+
+// S3Backend stores objects in a bucket using path-style requests signed
+// with AWS Signature V4. Metadata travels as x-amz-meta-* headers.
+type S3Backend struct {
+	cfg      S3Config
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+// NewS3 returns a backend for cfg.
+func NewS3(cfg S3Config) (*S3Backend, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage: s3 bucket is required")
+	}
+	u, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	return &S3Backend{cfg: cfg, endpoint: u, now: time.Now}, nil
+}
+
+const (
+	metaFilename = "X-Amz-Meta-Filename"
+	metaOwner    = "X-Amz-Meta-Owner"
+	metaChecksum = "X-Amz-Meta-Sha256"
+	metaCreated  = "X-Amz-Meta-Created"
+)
+
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader, meta Object) (Object, error) {
+	if err := validKey(key); err != nil {
+		return Object{}, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Object{}, err
+	}
+	sum := sha256.Sum256(data)
+
+	meta.Key = key
+	meta.Size = int64(len(data))
+	meta.Checksum = hex.EncodeToString(sum[:])
+	meta.CreatedAt = b.now().UTC()
+
+	h := http.Header{}
+	if meta.Mime != "" {
+		h.Set("Content-Type", meta.Mime)
+	}
+	if meta.Filename != "" {
+		h.Set(metaFilename, url.QueryEscape(meta.Filename))
+	}
+	if meta.Owner != "" {
+		h.Set(metaOwner, url.QueryEscape(meta.Owner))
+	}
+	h.Set(metaChecksum, meta.Checksum)
+	h.Set(metaCreated, meta.CreatedAt.Format(time.RFC3339Nano))
+
+	resp, err := b.do(ctx, http.MethodPut, key, nil, h, data)
+	if err != nil {
+		return Object{}, err
+	}
+	resp.Body.Close()
+	return meta, nil
+}
+
+func (b *S3Backend) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	if err := validKey(key); err != nil {
+		return nil, Object{}, err
+	}
+	resp, err := b.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	return resp.Body, objectFromHeader(key, resp), nil
+}
+
+func (b *S3Backend) Stat(ctx context.Context, key string) (Object, error) {
+	if err := validKey(key); err != nil {
+		return Object{}, err
+	}
+	resp, err := b.do(ctx, http.MethodHead, key, nil, nil, nil)
+	if err != nil {
+		return Object{}, err
+	}
+	resp.Body.Close()
+	return objectFromHeader(key, resp), nil
+}
+
+// Delete removes key. S3 answers 204 for missing keys too, so a Stat
+// first keeps ErrNotFound consistent with the local backend.
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	if _, err := b.Stat(ctx, key); err != nil {
+		return err
+	}
+	resp, err := b.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// List returns objects whose key starts with prefix, sorted by key.
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]Object, error) {
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		resp, err := b.do(ctx, http.MethodGet, "", q, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("storage: s3 list: %w", err)
+		}
+		for _, c := range page.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+
+	out := make([]Object, 0, len(keys))
+	for _, key := range keys {
+		obj, err := b.Stat(ctx, key)
+		if err == ErrNotFound {
+			continue // deleted since the listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, obj)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// do sends a signed request for key (the bucket itself when empty) and
+// returns the response for 2xx answers. 404 maps to ErrNotFound.
+func (b *S3Backend) do(ctx context.Context, method, key string, query url.Values, h http.Header, body []byte) (*http.Response, error) {
+	u := *b.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + b.cfg.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = canonicalURI(u.Path) // send exactly what is signed
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	req.ContentLength = int64(len(body))
+	b.sign(req, body)
+
+	resp, err := b.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound && key != "" {
+		return nil, ErrNotFound
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("storage: s3 %s %s: %s: %s", method, key, resp.Status, bytes.TrimSpace(msg))
+}
+
+func objectFromHeader(key string, resp *http.Response) Object {
+	h := resp.Header
+	obj := Object{
+		Key:      key,
+		Mime:     h.Get("Content-Type"),
+		Size:     resp.ContentLength,
+		Checksum: h.Get(metaChecksum),
+	}
+	obj.Filename, _ = url.QueryUnescape(h.Get(metaFilename))
+	obj.Owner, _ = url.QueryUnescape(h.Get(metaOwner))
+	if n, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64); err == nil {
+		obj.Size = n
+	}
+	if t, err := time.Parse(time.RFC3339Nano, h.Get(metaCreated)); err == nil {
+		obj.CreatedAt = t
+	} else if t, err := http.ParseTime(h.Get("Last-Modified")); err == nil {
+		obj.CreatedAt = t.UTC()
+	}
+	return obj
+}
+
+// validKey rejects keys S3 would normalise differently from the local
+// backend.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return ErrInvalidKey
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------
+// AWS Signature V4
+// ----------------------------------------------------
+
+const s3Algorithm = "AWS4-HMAC-SHA256"
+
+// sign adds x-amz-date, x-amz-content-sha256 and Authorization to req,
+// signing host and every header already set.
+func (b *S3Backend) sign(req *http.Request, body []byte) {
+	now := b.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonSum := sha256.Sum256([]byte(canonical))
+
+	scope := day + "/" + b.cfg.Region + "/s3/aws4_request"
+	toSign := s3Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonSum[:])
+
+	k := hmacSHA256([]byte("AWS4"+b.cfg.SecretKey), day)
+	k = hmacSHA256(k, b.cfg.Region)
+	k = hmacSHA256(k, "s3")
+	k = hmacSHA256(k, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(k, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s3Algorithm, b.cfg.AccessKey, scope, signedHeaders, sig))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// canonicalURI encodes each path segment once, as S3 expects.
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	segs := strings.Split(path, "/")
+	for i, s := range segs {
+		segs[i] = uriEncode(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+// canonicalQuery sorts and encodes query parameters; the same string is
+// sent and signed.
+func canonicalQuery(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but the RFC 3986 unreserved set.
+func uriEncode(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,142 @@
+// file: mini/storage/s3_test.go
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rskv-p/mini/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal path-style bucket that checks each request is signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data   []byte
+	header http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ak/") ||
+		r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/bucket/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !ok {
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		type content struct {
+			Key string `xml:"Key"`
+		}
+		var res struct {
+			XMLName  xml.Name  `xml:"ListBucketResult"`
+			Contents []content `xml:"Contents"`
+		}
+		for _, k := range keys {
+			res.Contents = append(res.Contents, content{Key: k})
+		}
+		_ = xml.NewEncoder(w).Encode(res)
+		return
+	}
+
+	obj, found := f.objects[key]
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = fakeObject{data: body, header: r.Header.Clone()}
+	case http.MethodGet, http.MethodHead:
+		if !found {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		for _, h := range []string{"Content-Type", "X-Amz-Meta-Filename", "X-Amz-Meta-Owner", "X-Amz-Meta-Sha256", "X-Amz-Meta-Created"} {
+			w.Header().Set(h, obj.header.Get(h))
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newS3Store(t *testing.T) *storage.Store {
+	srv := httptest.NewServer(&fakeS3{objects: map[string]fakeObject{}})
+	t.Cleanup(srv.Close)
+	b, err := storage.NewS3(storage.S3Config{
+		Endpoint:  srv.URL,
+		Bucket:    "bucket",
+		AccessKey: "ak",
+		SecretKey: "sk",
+	})
+	assert.NoError(t, err)
+	return storage.New(b)
+}
+
+func TestS3_PutGetListDelete(t *testing.T) {
+	st := newS3Store(t)
+	ctx := context.Background()
+
+	obj, err := st.PutBytes(ctx, "docs/a b.txt", []byte("hello"), storage.Object{
+		Filename: "a b.txt", Mime: "text/plain", Owner: "bob",
+	})
+	assert.NoError(t, err)
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), obj.Checksum)
+
+	data, got, err := st.GetBytes(ctx, "docs/a b.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, obj.Checksum, got.Checksum)
+	assert.Equal(t, "a b.txt", got.Filename)
+	assert.Equal(t, "text/plain", got.Mime)
+	assert.Equal(t, "bob", got.Owner)
+	assert.Equal(t, int64(5), got.Size)
+	assert.True(t, obj.CreatedAt.Equal(got.CreatedAt))
+
+	_, err = st.PutBytes(ctx, "other.txt", []byte("x"), storage.Object{})
+	assert.NoError(t, err)
+	list, err := st.List(ctx, "docs/")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "docs/a b.txt", list[0].Key)
+
+	assert.NoError(t, st.Delete(ctx, "docs/a b.txt"))
+	_, err = st.Stat(ctx, "docs/a b.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, st.Delete(ctx, "docs/a b.txt"), storage.ErrNotFound)
+}
+
+func TestS3_InvalidKey(t *testing.T) {
+	st := newS3Store(t)
+	for _, key := range []string{"", "/abs", "a/../b", "a//b"} {
+		_, err := st.PutBytes(context.Background(), key, nil, storage.Object{})
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
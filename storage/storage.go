@@ -0,0 +1,113 @@
+// file: mini/storage/storage.go
+// Package storage persists files behind a pluggable backend and exposes
+// them as store.* actions.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/rskv-p/mini/transport"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Object describes a stored file.
+type Object struct {
+	Key       string    `json:"key"`
+	Filename  string    `json:"filename,omitempty"`
+	Mime      string    `json:"mime,omitempty"`
+	Size      int64     `json:"size"`
+	Checksum  string    `json:"checksum"` // sha256, hex
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IBackend stores object data and metadata.
+// Put fills Key, Size, Checksum and CreatedAt in the returned Object.
+type IBackend interface {
+	Put(ctx context.Context, key string, r io.Reader, meta Object) (Object, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Stat(ctx context.Context, key string) (Object, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Store is the Go API over a backend.
+type Store struct {
+	backend IBackend
+}
+
+// New returns a store using backend.
+func New(backend IBackend) *Store {
+	return &Store{backend: backend}
+}
+
+// Backend returns the underlying backend.
+func (s *Store) Backend() IBackend { return s.backend }
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, meta Object) (Object, error) {
+	return s.backend.Put(ctx, key, r, meta)
+}
+
+// PutBytes stores data under key.
+func (s *Store) PutBytes(ctx context.Context, key string, data []byte, meta Object) (Object, error) {
+	return s.backend.Put(ctx, key, bytes.NewReader(data), meta)
+}
+
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	return s.backend.Get(ctx, key)
+}
+
+// GetBytes reads the whole object into memory.
+func (s *Store) GetBytes(ctx context.Context, key string) ([]byte, Object, error) {
+	rc, obj, err := s.backend.Get(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	return data, obj, err
+}
+
+func (s *Store) Stat(ctx context.Context, key string) (Object, error) {
+	return s.backend.Stat(ctx, key)
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.backend.Delete(ctx, key)
+}
+
+func (s *Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	return s.backend.List(ctx, prefix)
+}
+
+// ----------------------------------------------------
+// Transport integration
+// ----------------------------------------------------
+
+// ReceiveFiles returns a handler that persists completed chunked uploads
+// (see transport.SendFile). Objects are keyed by file ID and keep the
+// sender's filename and mime type. onStored, if set, is called per upload.
+func (s *Store) ReceiveFiles(owner string, onStored func(Object, error)) transport.MsgHandler {
+	return transport.ReceiveFileHandler(func(ctx context.Context, ch transport.FileChunk, isLast bool) error {
+		if !isLast {
+			return nil
+		}
+		obj, err := s.PutBytes(ctx, ch.FileID, ch.ChunkBytes, Object{
+			Filename: ch.Filename,
+			Mime:     ch.Mime,
+			Owner:    owner,
+		})
+		if onStored != nil {
+			onStored(obj, err)
+		}
+		return err
+	})
+}
@@ -0,0 +1,299 @@
+// file: mini/transport/bundle.go
+package transport
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+)
+
+// BundleMime marks a file transfer as a tar bundle.
+const BundleMime = "application/x-tar"
+
+// paxMime carries the per-file mime type in the tar header.
+const paxMime = "MINI.mime"
+
+var ErrUnsafePath = errors.New("transport: unsafe path in bundle")
+
+// ----------------------------------------------------
+// Bundle metadata
+// ----------------------------------------------------
+
+// BundleFile is one in-memory file to pack into a bundle.
+type BundleFile struct {
+	Name string
+	Data []byte
+	Mime string
+	Mode fs.FileMode
+}
+
+// BundleEntry describes a file inside a received bundle.
+type BundleEntry struct {
+	Name    string      `json:"name"`
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+	Mime    string      `json:"mime,omitempty"`
+}
+
+// BundleOptions controls how a bundle is sent.
+type BundleOptions struct {
+	ChunkSize  int                    // default constant.MaxFileChunkSize
+	Filter     func(name string) bool // SendDir only; nil includes everything
+	OnProgress func(sent, total int)  // called after each published chunk
+}
+
+// ----------------------------------------------------
+// Packing
+// ----------------------------------------------------
+
+// PackFiles writes files into a tar archive.
+func PackFiles(files []BundleFile) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+	for _, f := range files {
+		name, err := cleanBundlePath(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    int64(mode.Perm()),
+			Size:    int64(len(f.Data)),
+			ModTime: now,
+			Format:  tar.FormatPAX,
+		}
+		if f.Mime != "" {
+			hdr.PAXRecords = map[string]string{paxMime: f.Mime}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("bundle header %s: %w", name, err)
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return nil, fmt.Errorf("bundle write %s: %w", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PackDir writes the regular files under dir into a tar archive with
+// slash-separated paths relative to dir. filter may be nil.
+func PackDir(dir string, filter func(name string) bool) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if filter != nil && !filter(name) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("bundle header %s: %w", name, err)
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ----------------------------------------------------
+// Sending
+// ----------------------------------------------------
+
+// SendDir packs dir into a tar bundle and sends it over the chunking layer.
+// The bundle filename defaults to the directory name plus ".tar".
+func (t *Transport) SendDir(msg codec.IMessage, subject, dir string, opts BundleOptions) error {
+	data, err := PackDir(dir, opts.Filter)
+	if err != nil {
+		return fmt.Errorf("pack %s: %w", dir, err)
+	}
+	if msg.GetString("filename") == "" {
+		msg.Set("filename", filepath.Base(filepath.Clean(dir))+".tar")
+	}
+	return t.sendBundle(msg, subject, data, opts)
+}
+
+// SendBundle packs files into a tar bundle and sends it.
+func (t *Transport) SendBundle(msg codec.IMessage, subject string, files []BundleFile, opts BundleOptions) error {
+	data, err := PackFiles(files)
+	if err != nil {
+		return err
+	}
+	if msg.GetString("filename") == "" {
+		msg.Set("filename", "bundle.tar")
+	}
+	return t.sendBundle(msg, subject, data, opts)
+}
+
+func (t *Transport) sendBundle(msg codec.IMessage, subject string, data []byte, opts BundleOptions) error {
+	size := opts.ChunkSize
+	if size <= 0 {
+		size = constant.MaxFileChunkSize
+	}
+	msg.Set("mime", BundleMime)
+	return t.sendChunks(msg, subject, data, size, opts.OnProgress)
+}
+
+// ----------------------------------------------------
+// Receiving
+// ----------------------------------------------------
+
+// BundleReceiverHooks configures ReceiveBundle.
+type BundleReceiverHooks struct {
+	// Dir extracts selected files below this directory when set.
+	Dir string
+	// Select limits extraction; nil selects every file.
+	Select func(BundleEntry) bool
+	// OnChunk reports transfer progress.
+	OnChunk func(FileChunk)
+	// OnFile is called with the contents of each selected file.
+	OnFile func(BundleEntry, []byte) error
+	// OnComplete is called once the bundle is processed.
+	OnComplete func(meta FileChunk, entries []BundleEntry, err error)
+	// OnTimeout is called when an incomplete bundle expires.
+	OnTimeout func(fileID string)
+}
+
+// ReceiveBundle assembles bundle chunks and unpacks the archive.
+func ReceiveBundle(hooks BundleReceiverHooks) MsgHandler {
+	return ReceiveFileWithHooks(FileReceiverHooks{
+		OnChunk:   hooks.OnChunk,
+		OnTimeout: hooks.OnTimeout,
+		OnComplete: func(full []byte, meta FileChunk) {
+			entries, err := ReadBundle(full, hooks.Select, func(e BundleEntry, data []byte) error {
+				if hooks.Dir != "" {
+					if err := writeBundleFile(hooks.Dir, e, data); err != nil {
+						return err
+					}
+				}
+				if hooks.OnFile != nil {
+					return hooks.OnFile(e, data)
+				}
+				return nil
+			})
+			if hooks.OnComplete != nil {
+				hooks.OnComplete(meta, entries, err)
+			}
+		},
+	})
+}
+
+// ReadBundle walks the tar archive in data and calls fn for every regular
+// file accepted by sel (nil accepts all). It returns the visited entries.
+func ReadBundle(data []byte, sel func(BundleEntry) bool, fn func(BundleEntry, []byte) error) ([]BundleEntry, error) {
+	tr := tar.NewReader(bytes.NewReader(data))
+	var entries []BundleEntry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, fmt.Errorf("read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := cleanBundlePath(hdr.Name)
+		if err != nil {
+			return entries, err
+		}
+		entry := BundleEntry{
+			Name:    name,
+			Size:    hdr.Size,
+			Mode:    fs.FileMode(hdr.Mode).Perm(),
+			ModTime: hdr.ModTime,
+			Mime:    hdr.PAXRecords[paxMime],
+		}
+		if sel != nil && !sel(entry) {
+			continue
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			return entries, fmt.Errorf("read %s: %w", name, err)
+		}
+		entries = append(entries, entry)
+		if fn != nil {
+			if err := fn(entry, body); err != nil {
+				return entries, err
+			}
+		}
+	}
+}
+
+// ExtractBundle writes the selected files of data below dir.
+func ExtractBundle(data []byte, dir string, sel func(BundleEntry) bool) ([]BundleEntry, error) {
+	return ReadBundle(data, sel, func(e BundleEntry, body []byte) error {
+		return writeBundleFile(dir, e, body)
+	})
+}
+
+func writeBundleFile(dir string, e BundleEntry, data []byte) error {
+	dst := filepath.Join(dir, filepath.FromSlash(e.Name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	mode := e.Mode
+	if mode == 0 {
+		mode = 0o644
+	}
+	return os.WriteFile(dst, data, mode)
+}
+
+// cleanBundlePath rejects absolute paths and parent traversal.
+func cleanBundlePath(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+	return clean, nil
+}
@@ -0,0 +1,102 @@
+// file: mini/transport/bundle_test.go
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle_SendReceive(t *testing.T) {
+	mt := newMockTransport()
+	mt.overridePublish()
+
+	files := []BundleFile{
+		{Name: "app.json", Data: []byte(`{"port":8080}`), Mime: "application/json"},
+		{Name: "certs/ca.pem", Data: []byte("PEM"), Mode: 0o600},
+		{Name: "readme.txt", Data: []byte(strings.Repeat("x", 3000))},
+	}
+
+	var progress []int
+	msg := codec.NewMessage("")
+	err := mt.SendBundle(msg, "bundle.topic", files, BundleOptions{
+		ChunkSize:  1024,
+		OnProgress: func(sent, total int) { progress = append(progress, sent) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(mt.published), len(progress))
+	assert.Greater(t, len(progress), 1)
+
+	dir := t.TempDir()
+	var (
+		meta    FileChunk
+		entries []BundleEntry
+		done    error
+		got     = map[string]string{}
+	)
+	handler := ReceiveBundle(BundleReceiverHooks{
+		Dir:    dir,
+		Select: func(e BundleEntry) bool { return e.Name != "readme.txt" },
+		OnFile: func(e BundleEntry, data []byte) error {
+			got[e.Name] = string(data)
+			return nil
+		},
+		OnComplete: func(m FileChunk, es []BundleEntry, err error) {
+			meta, entries, done = m, es, err
+		},
+	})
+	for _, data := range mt.published {
+		assert.NoError(t, handler(data))
+	}
+
+	assert.NoError(t, done)
+	assert.Equal(t, "bundle.tar", meta.Filename)
+	assert.Equal(t, BundleMime, meta.Mime)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "application/json", entries[0].Mime)
+	assert.Equal(t, "PEM", got["certs/ca.pem"])
+
+	pem, err := os.ReadFile(filepath.Join(dir, "certs", "ca.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, "PEM", string(pem))
+	_, err = os.Stat(filepath.Join(dir, "readme.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestBundle_PackDir(t *testing.T) {
+	src := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("A"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "sub", "b.txt"), []byte("B"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "skip.log"), []byte("L"), 0644))
+
+	data, err := PackDir(src, func(name string) bool { return !strings.HasSuffix(name, ".log") })
+	assert.NoError(t, err)
+
+	dst := t.TempDir()
+	entries, err := ExtractBundle(data, dst, nil)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	b, err := os.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "B", string(b))
+
+	mt := newMockTransport()
+	mt.overridePublish()
+	msg := codec.NewMessage("")
+	assert.NoError(t, mt.SendDir(msg, "dir.topic", src, BundleOptions{}))
+	assert.Equal(t, filepath.Base(src)+".tar", msg.GetString("filename"))
+	assert.Len(t, mt.published, 1)
+}
+
+func TestBundle_UnsafePaths(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/abs", "a/../../b", ""} {
+		_, err := PackFiles([]BundleFile{{Name: name}})
+		assert.ErrorIs(t, err, ErrUnsafePath, name)
+	}
+}
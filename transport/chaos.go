@@ -0,0 +1,113 @@
+// file: mini/transport/chaos.go
+package transport
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ----------------------------------------------------
+// Chaos / fault injection (development only)
+// ----------------------------------------------------
+
+var ErrChaosInjected = errors.New("transport: chaos fault injected")
+
+// ChaosRule injects faults into messages whose subject matches Subject
+// (exact, or a prefix when it ends in "*"; empty matches everything).
+// Rates are probabilities in [0,1].
+type ChaosRule struct {
+	Subject   string        `json:"subject"`
+	Latency   time.Duration `json:"latency"`
+	Jitter    time.Duration `json:"jitter"`
+	ErrorRate float64       `json:"error_rate"`
+	DropRate  float64       `json:"drop_rate"`
+}
+
+// Matches reports whether the rule applies to subject.
+func (r ChaosRule) Matches(subject string) bool {
+	if r.Subject == "" {
+		return true
+	}
+	return matchSubjects([]string{r.Subject}, subject, false)
+}
+
+// ChaosEffect is the fault chosen for one message.
+type ChaosEffect struct {
+	Delay time.Duration
+	Fail  bool
+	Drop  bool
+}
+
+// Chaos evaluates rules with its own random source.
+type Chaos struct {
+	rules []ChaosRule
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewChaos returns an injector for rules.
+func NewChaos(rules ...ChaosRule) *Chaos {
+	return &Chaos{rules: rules, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Seed makes decisions reproducible.
+func (c *Chaos) Seed(seed int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rnd = rand.New(rand.NewSource(seed))
+}
+
+// Rules returns the configured rules.
+func (c *Chaos) Rules() []ChaosRule { return c.rules }
+
+// Decide picks the effect for subject using the first matching rule.
+func (c *Chaos) Decide(subject string) ChaosEffect {
+	for _, r := range c.rules {
+		if !r.Matches(subject) {
+			continue
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		eff := ChaosEffect{Delay: r.Latency}
+		if r.Jitter > 0 {
+			eff.Delay += time.Duration(c.rnd.Int63n(int64(r.Jitter)))
+		}
+		eff.Drop = r.DropRate > 0 && c.rnd.Float64() < r.DropRate
+		eff.Fail = !eff.Drop && r.ErrorRate > 0 && c.rnd.Float64() < r.ErrorRate
+		return eff
+	}
+	return ChaosEffect{}
+}
+
+// Middleware injects latency, errors and silent drops into transport
+// traffic. Dropped messages are not passed on and report no error.
+func (c *Chaos) Middleware() MiddlewareFunc {
+	return func(next TransportHandler) TransportHandler {
+		return func(ctx context.Context, subject string, data []byte) error {
+			eff := c.Decide(subject)
+			if eff.Delay > 0 {
+				select {
+				case <-time.After(eff.Delay):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			switch {
+			case eff.Drop:
+				return nil
+			case eff.Fail:
+				return ErrChaosInjected
+			}
+			return next(ctx, subject, data)
+		}
+	}
+}
+
+// ChaosMiddleware is shorthand for NewChaos(rules...).Middleware().
+func ChaosMiddleware(rules ...ChaosRule) MiddlewareFunc {
+	return NewChaos(rules...).Middleware()
+}
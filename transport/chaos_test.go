@@ -0,0 +1,53 @@
+// file: mini/transport/chaos_test.go
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos_Decide(t *testing.T) {
+	c := NewChaos(
+		ChaosRule{Subject: "orders.*", ErrorRate: 1},
+		ChaosRule{Subject: "billing", DropRate: 1, Latency: time.Millisecond},
+	)
+
+	assert.True(t, c.Decide("orders.created").Fail)
+	eff := c.Decide("billing")
+	assert.True(t, eff.Drop)
+	assert.False(t, eff.Fail)
+	assert.Equal(t, time.Millisecond, eff.Delay)
+	assert.Equal(t, ChaosEffect{}, c.Decide("users"))
+}
+
+func TestChaos_Middleware(t *testing.T) {
+	calls := 0
+	next := func(ctx context.Context, subject string, data []byte) error {
+		calls++
+		return nil
+	}
+	h := ChaosMiddleware(
+		ChaosRule{Subject: "fail", ErrorRate: 1},
+		ChaosRule{Subject: "drop", DropRate: 1},
+	)(next)
+
+	assert.ErrorIs(t, h(context.Background(), "fail", nil), ErrChaosInjected)
+	assert.NoError(t, h(context.Background(), "drop", nil))
+	assert.NoError(t, h(context.Background(), "ok", nil))
+	assert.Equal(t, 1, calls)
+}
+
+func TestChaos_Rates(t *testing.T) {
+	c := NewChaos(ChaosRule{ErrorRate: 0.3})
+	c.Seed(1)
+	fails := 0
+	for i := 0; i < 1000; i++ {
+		if c.Decide("any").Fail {
+			fails++
+		}
+	}
+	assert.InDelta(t, 300, fails, 60)
+}
@@ -226,6 +226,21 @@ func (c *Conn) scheduleTTLUnsubscribe(subject string, sub *Subscription, ttl tim
 	})
 }
 
+// Unsubscribe stops the consumer for subject and forgets it so the subject
+// can be subscribed again.
+func (c *Conn) Unsubscribe(subject string) error {
+	c.mu.Lock()
+	consumer, ok := c.consumers[subject]
+	delete(c.consumers, subject)
+	c.mu.Unlock()
+
+	if ok {
+		consumer.Stop()
+		<-consumer.StopChan
+	}
+	return nil
+}
+
 func (s *Subscription) cancel() error {
 	if s.consumer != nil {
 		s.consumer.Stop()
@@ -0,0 +1,78 @@
+// file: mini/transport/correlation.go
+package transport
+
+import (
+	"context"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+)
+
+// ----------------------------------------------------
+// Correlation and causation IDs
+// ----------------------------------------------------
+//
+// correlation_id is shared by every message of one multi-hop flow.
+// causation_id is the context ID of the message that caused this one.
+
+type correlationKey struct{}
+type causationKey struct{}
+
+// WithCorrelation stores correlation and causation IDs in ctx.
+func WithCorrelation(ctx context.Context, correlationID, causationID string) context.Context {
+	if correlationID != "" {
+		ctx = context.WithValue(ctx, correlationKey{}, correlationID)
+	}
+	if causationID != "" {
+		ctx = context.WithValue(ctx, causationKey{}, causationID)
+	}
+	return ctx
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx.
+func CorrelationIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(correlationKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// CausationIDFromContext returns the causation ID stored in ctx.
+func CausationIDFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(causationKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// ContextFromMessage returns ctx prepared for downstream calls made while
+// handling msg: same correlation ID, with msg as the cause.
+func ContextFromMessage(ctx context.Context, msg codec.IMessage) context.Context {
+	correlationID := msg.GetHeader(constant.HeaderCorrelationID)
+	if correlationID == "" {
+		correlationID = msg.GetString("trace_id")
+	}
+	if correlationID == "" {
+		correlationID = msg.GetContextID()
+	}
+	ctx = WithCorrelation(ctx, correlationID, msg.GetContextID())
+	if traceID := msg.GetString("trace_id"); traceID != "" {
+		ctx = WithTrace(ctx, traceID)
+	}
+	return traceContextFromMessage(ctx, msg)
+}
+
+// ApplyCorrelation stamps correlation headers from ctx onto msg.
+// Headers already present on msg are kept.
+func ApplyCorrelation(ctx context.Context, msg codec.IMessage) {
+	if msg.GetHeader(constant.HeaderCorrelationID) == "" {
+		if id := CorrelationIDFromContext(ctx); id != "" {
+			msg.SetHeader(constant.HeaderCorrelationID, id)
+		}
+	}
+	if msg.GetHeader(constant.HeaderCausationID) == "" {
+		if id := CausationIDFromContext(ctx); id != "" {
+			msg.SetHeader(constant.HeaderCausationID, id)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// file: mini/transport/correlation_test.go
+package transport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextFromMessage(t *testing.T) {
+	in := codec.NewMessage("request")
+	in.SetContextID("ctx-1")
+	in.Set("trace_id", "trace-1")
+	in.SetHeader(constant.HeaderCorrelationID, "corr-1")
+
+	ctx := ContextFromMessage(context.Background(), in)
+	assert.Equal(t, "corr-1", CorrelationIDFromContext(ctx))
+	assert.Equal(t, "ctx-1", CausationIDFromContext(ctx))
+	assert.Equal(t, "trace-1", TraceIDFromContext(ctx))
+
+	// fallback to trace_id when no correlation header is present
+	bare := codec.NewMessage("request")
+	bare.SetContextID("ctx-2")
+	bare.Set("trace_id", "trace-2")
+	ctx = ContextFromMessage(context.Background(), bare)
+	assert.Equal(t, "trace-2", CorrelationIDFromContext(ctx))
+}
+
+func TestApplyCorrelation_KeepsExisting(t *testing.T) {
+	ctx := WithCorrelation(context.Background(), "corr", "cause")
+
+	msg := codec.NewMessage("")
+	ApplyCorrelation(ctx, msg)
+	assert.Equal(t, "corr", msg.GetHeader(constant.HeaderCorrelationID))
+	assert.Equal(t, "cause", msg.GetHeader(constant.HeaderCausationID))
+
+	other := codec.NewMessage("")
+	other.SetHeader(constant.HeaderCorrelationID, "mine")
+	ApplyCorrelation(ctx, other)
+	assert.Equal(t, "mine", other.GetHeader(constant.HeaderCorrelationID))
+}
+
+func TestPublish_StampsCorrelation(t *testing.T) {
+	conn := &dlqConn{}
+	tr := New()
+	tr.conn = conn
+
+	data, _ := codec.Marshal(codec.NewMessage("event"))
+	assert.NoError(t, tr.Publish("orders", data))
+
+	out := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(conn.published["orders"], out))
+	assert.NotEmpty(t, out.GetHeader(constant.HeaderCorrelationID))
+	assert.Equal(t, out.GetString("trace_id"), out.GetHeader(constant.HeaderCorrelationID))
+}
@@ -0,0 +1,124 @@
+// file: mini/transport/deadletter.go
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rskv-p/mini/logger"
+)
+
+// ----------------------------------------------------
+// Dead-letter record and sink interface
+// ----------------------------------------------------
+
+// DeadLetter describes a message that exhausted its delivery attempts.
+type DeadLetter struct {
+	Subject  string    `json:"subject"`
+	Payload  []byte    `json:"payload"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// IDeadLetterSink stores or forwards dead letters.
+type IDeadLetterSink interface {
+	WriteDeadLetter(DeadLetter) error
+}
+
+// Ensure interface compliance
+var (
+	_ IDeadLetterSink = (*SubjectSink)(nil)
+	_ IDeadLetterSink = (*FileSink)(nil)
+)
+
+// newDeadLetter builds a record from the final retry state.
+func newDeadLetter(subject string, data []byte, err error, attempts int) DeadLetter {
+	dl := DeadLetter{
+		Subject:  subject,
+		Payload:  data,
+		Attempts: attempts,
+		FailedAt: time.Now(),
+	}
+	if err != nil {
+		dl.Error = err.Error()
+	}
+	return dl
+}
+
+// writeDeadLetter passes a dead letter to the DLQ subject and all sinks.
+func (t *Transport) writeDeadLetter(dl DeadLetter) {
+	sinks := t.opts.DeadLetterSinks
+	if t.opts.DeadLetterSubject != "" && t.connection() != nil {
+		sinks = append([]IDeadLetterSink{NewSubjectSink(t.opts.DeadLetterSubject, t.publishConn)}, sinks...)
+	}
+	if len(sinks) == 0 {
+		return
+	}
+	if t.opts.Metrics != nil {
+		t.opts.Metrics.IncCounter("transport_dead_letters_total")
+	}
+	for _, sink := range sinks {
+		if err := sink.WriteDeadLetter(dl); err != nil && t.opts.Logger != nil {
+			t.opts.Logger.Warn("dead-letter sink failed for %s: %v", dl.Subject, err)
+		}
+	}
+}
+
+// ----------------------------------------------------
+// Subject sink (republish to a DLQ subject)
+// ----------------------------------------------------
+
+// SubjectSink republishes dead letters as JSON to a DLQ subject.
+// Publishing bypasses the retry chain to avoid dead-letter loops.
+type SubjectSink struct {
+	subject string
+	publish func(subject string, data []byte) error
+}
+
+// NewSubjectSink returns a sink publishing to subject via publish.
+func NewSubjectSink(subject string, publish func(subject string, data []byte) error) *SubjectSink {
+	return &SubjectSink{subject: subject, publish: publish}
+}
+
+// WriteDeadLetter publishes the record unless it came from the DLQ itself.
+func (s *SubjectSink) WriteDeadLetter(dl DeadLetter) error {
+	if dl.Subject == s.subject {
+		return fmt.Errorf("dead-letter loop on %s", s.subject)
+	}
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	return s.publish(s.subject, data)
+}
+
+// ----------------------------------------------------
+// File sink (JSON lines with size-based rotation)
+// ----------------------------------------------------
+
+// FileSink appends dead letters as JSON lines to a logger.RotatingFile.
+type FileSink struct {
+	*logger.RotatingFile
+}
+
+// NewFileSink opens (or creates) path for appending; see
+// logger.NewRotatingFile for the rotation limits.
+func NewFileSink(path string, maxBytes int64, maxBackups int) (*FileSink, error) {
+	f, err := logger.NewRotatingFile(path, maxBytes, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{RotatingFile: f}, nil
+}
+
+// WriteDeadLetter appends one JSON line, rotating first if needed.
+func (s *FileSink) WriteDeadLetter(dl DeadLetter) error {
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	_, err = s.Write(append(line, '\n'))
+	return err
+}
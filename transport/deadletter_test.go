@@ -0,0 +1,113 @@
+// file: mini/transport/deadletter_test.go
+package transport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+// dlqConn fails publishes to one subject and records all others.
+type dlqConn struct {
+	mockIConn
+	failSubject string
+	mu          sync.Mutex
+	published   map[string][]byte
+}
+
+func (c *dlqConn) Publish(subject string, data []byte) error {
+	if subject == c.failSubject {
+		return errors.New("publish error")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.published == nil {
+		c.published = make(map[string][]byte)
+	}
+	c.published[subject] = data
+	return nil
+}
+
+type memorySink struct {
+	letters []DeadLetter
+}
+
+func (s *memorySink) WriteDeadLetter(dl DeadLetter) error {
+	s.letters = append(s.letters, dl)
+	return nil
+}
+
+func TestDeadLetter_SubjectAndSink(t *testing.T) {
+	sink := &memorySink{}
+	conn := &dlqConn{failSubject: "orders"}
+
+	tr := New(WithDeadLetterSubject("orders.dlq"), WithDeadLetterSink(sink))
+	tr.conn = conn
+
+	data, _ := codec.Marshal(codec.NewMessage("event"))
+	err := tr.Publish("orders", data)
+	assert.Error(t, err)
+
+	assert.Len(t, sink.letters, 1)
+	assert.Equal(t, "orders", sink.letters[0].Subject)
+	assert.Equal(t, "publish error", sink.letters[0].Error)
+	assert.Equal(t, 1, sink.letters[0].Attempts)
+
+	raw, ok := conn.published["orders.dlq"]
+	assert.True(t, ok)
+	var dl DeadLetter
+	assert.NoError(t, json.Unmarshal(raw, &dl))
+	assert.Equal(t, "orders", dl.Subject)
+	assert.NotEmpty(t, dl.Payload)
+}
+
+func TestSubjectSink_Loop(t *testing.T) {
+	s := NewSubjectSink("dlq", func(string, []byte) error { return nil })
+	assert.Error(t, s.WriteDeadLetter(DeadLetter{Subject: "dlq"}))
+}
+
+func TestFileSink_WriteAndRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dlq", "dead.log")
+	sink, err := NewFileSink(path, 150, 2)
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, sink.WriteDeadLetter(DeadLetter{
+			Subject:  "a.b",
+			Payload:  []byte("payload"),
+			Error:    "boom",
+			Attempts: i + 1,
+			FailedAt: time.Unix(0, 0),
+		}))
+	}
+
+	_, err = os.Stat(path + ".1")
+	assert.NoError(t, err)
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	assert.True(t, scanner.Scan())
+	var dl DeadLetter
+	assert.NoError(t, json.Unmarshal(scanner.Bytes(), &dl))
+	assert.Equal(t, "a.b", dl.Subject)
+}
+
+func TestFileSink_Closed(t *testing.T) {
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "dead.log"), 0, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Close())
+	assert.Error(t, sink.WriteDeadLetter(DeadLetter{Subject: "x"}))
+}
@@ -0,0 +1,69 @@
+// file: mini/transport/drain.go
+package transport
+
+import (
+	"fmt"
+	"time"
+)
+
+// DrainReport summarizes a transport-wide drain.
+type DrainReport struct {
+	Topics    int           `json:"topics"`
+	InFlight  int64         `json:"in_flight"` // handlers running when the drain started
+	Completed int64         `json:"completed"` // handlers that finished during the drain
+	Requeued  int64         `json:"requeued"`  // messages rejected for redelivery
+	Abandoned int64         `json:"abandoned"` // handlers still running at the deadline
+	TimedOut  bool          `json:"timed_out"`
+	Elapsed   time.Duration `json:"elapsed"`
+}
+
+// Drain stops message intake on every subscription (RDY 0), requeues
+// anything NSQ still delivers and waits up to timeout for running handlers.
+// Subscriptions stay paused afterwards; Close releases them. On timeout the
+// report is returned together with ErrDrainTimeout.
+func (t *Transport) Drain(timeout time.Duration) (DrainReport, error) {
+	start := time.Now()
+
+	t.subsMu.RLock()
+	entries := make([]*topicSub, 0, len(t.subs))
+	for _, e := range t.subs {
+		entries = append(entries, e)
+	}
+	t.subsMu.RUnlock()
+
+	report := DrainReport{Topics: len(entries)}
+	requeued := make([]int64, len(entries))
+	delivered := make([]int64, len(entries))
+	for i, e := range entries {
+		e.setPaused(true)
+		e.setInFlight(0)
+		report.InFlight += e.inFlight.Load()
+		requeued[i] = e.requeued.Load()
+		delivered[i] = e.delivered.Load()
+	}
+
+	// Wait on the per-topic counters: they are incremented under the same
+	// lock setPaused takes, unlike t.active.
+	finished := waitTimeout(func() {
+		for _, e := range entries {
+			e.active.Wait()
+		}
+	}, timeout)
+
+	for i, e := range entries {
+		report.Requeued += e.requeued.Load() - requeued[i]
+		report.Completed += e.delivered.Load() - delivered[i]
+		report.Abandoned += e.inFlight.Load()
+	}
+	report.TimedOut = !finished
+	report.Elapsed = time.Since(start)
+
+	if t.opts.Logger != nil {
+		t.opts.Logger.Info("transport drained: topics=%d completed=%d requeued=%d abandoned=%d in %v",
+			report.Topics, report.Completed, report.Requeued, report.Abandoned, report.Elapsed)
+	}
+	if report.TimedOut {
+		return report, fmt.Errorf("%w: %d handlers still running", ErrDrainTimeout, report.Abandoned)
+	}
+	return report, nil
+}
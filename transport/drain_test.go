@@ -0,0 +1,123 @@
+// file: mini/transport/drain_test.go
+package transport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_Drain(t *testing.T) {
+	tr := New()
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.NoError(t, tr.SubscribeTopic("work", func([]byte) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	assert.NoError(t, tr.SubscribeTopic("idle", func([]byte) error { return nil }))
+
+	go func() { _ = conn.push("work", []byte(`{}`)) }()
+	<-started
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		assert.ErrorIs(t, conn.push("idle", []byte(`{}`)), ErrTopicPaused)
+		close(release)
+	}()
+
+	report, err := tr.Drain(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Topics)
+	assert.Equal(t, int64(1), report.InFlight)
+	assert.Equal(t, int64(1), report.Completed)
+	assert.Equal(t, int64(1), report.Requeued)
+	assert.Equal(t, int64(0), report.Abandoned)
+	assert.False(t, report.TimedOut)
+
+	for _, s := range tr.ListSubscriptions() {
+		assert.True(t, s.Paused)
+	}
+}
+
+func TestTransport_CloseDrainTimeout(t *testing.T) {
+	tr := New(WithDrainTimeout(10 * time.Millisecond))
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{})
+	assert.NoError(t, tr.SubscribeTopic("stuck", func([]byte) error {
+		close(started)
+		<-block
+		return nil
+	}))
+	go func() { _ = conn.push("stuck", []byte(`{}`)) }()
+	<-started
+
+	report, err := tr.Drain(5 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+	assert.True(t, report.TimedOut)
+	assert.Equal(t, int64(1), report.Abandoned)
+
+	// Close must not block on the stuck handler.
+	done := make(chan struct{})
+	go func() {
+		_ = tr.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on in-flight handler")
+	}
+	assert.True(t, conn.closed)
+}
+
+func TestTransport_CloseWaitsForReplies(t *testing.T) {
+	tr := New()
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	started := make(chan struct{})
+	replied := make(chan error, 1)
+	assert.NoError(t, tr.SubscribeTopic("work", func([]byte) error {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		replied <- tr.Publish("reply.x", []byte(`{}`))
+		return nil
+	}))
+	go func() { _ = conn.push("work", []byte(`{}`)) }()
+	<-started
+
+	assert.NoError(t, tr.Close())
+	assert.NoError(t, <-replied)
+}
+
+func TestTransport_PublishAfterDrainTimeout(t *testing.T) {
+	tr := New(WithDrainTimeout(5 * time.Millisecond))
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	replied := make(chan error, 1)
+	assert.NoError(t, tr.SubscribeTopic("stuck", func([]byte) error {
+		close(started)
+		<-release
+		replied <- tr.Publish("reply.x", []byte(`{}`))
+		return nil
+	}))
+	go func() { _ = conn.push("stuck", []byte(`{}`)) }()
+	<-started
+
+	assert.NoError(t, tr.Close())
+	close(release)
+	assert.ErrorIs(t, <-replied, ErrDisconnected)
+}
@@ -31,6 +31,17 @@ type FileChunk struct {
 // ----------------------------------------------------
 
 func (t *Transport) SendFile(msg codec.IMessage, subject string, file []byte, chunkSize int) error {
+	return t.sendChunks(msg, subject, file, chunkSize, nil)
+}
+
+// sendChunks publishes file in chunks, reporting each sent chunk to progress.
+func (t *Transport) sendChunks(
+	msg codec.IMessage,
+	subject string,
+	file []byte,
+	chunkSize int,
+	progress func(sent, total int),
+) error {
 	fileSize := len(file)
 	total := chunkCount(fileSize, chunkSize)
 	reader := bytes.NewReader(file)
@@ -82,6 +93,9 @@ func (t *Transport) SendFile(msg codec.IMessage, subject string, file []byte, ch
 		if err := t.Publish(subject, data); err != nil {
 			return fmt.Errorf("publish chunk %d: %w", index, err)
 		}
+		if progress != nil {
+			progress(index+1, total)
+		}
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
 )
 
 // ----------------------------------------------------
@@ -29,7 +30,7 @@ func (t *Transport) RequestWithContext(
 	req []byte,
 	handler ResponseHandler,
 ) error {
-	if t.conn == nil {
+	if t.connection() == nil {
 		return ErrDisconnected
 	}
 
@@ -40,11 +41,36 @@ func (t *Transport) RequestWithContext(
 	}
 	setDefaultTrace(ctx, msg)
 	traceID := msg.GetString("trace_id")
+	if msg.GetReplyTo() == "" {
+		// Set here rather than in the conn so the signature covers it.
+		msg.SetReplyTo("reply." + msg.GetContextID())
+	}
+	if err := t.signMessage(msg); err != nil {
+		return err
+	}
+	if err := t.sealData(subject, msg); err != nil {
+		return err
+	}
+	sealed := IsSealed(msg)
 	req, _ = codec.Marshal(msg)
 
+	call := &outboundCall{kind: RecordKindRequest}
 	base := func(subj string, data []byte) error {
+		conn := t.connection()
+		if conn == nil {
+			return ErrDisconnected
+		}
 		start := time.Now()
-		respMsg, err := t.conn.Request(subj, data, t.opts.Timeout)
+		respMsg, err := conn.Request(subj, data, t.opts.Timeout)
+		if err == nil {
+			err = t.openReply(subj, sealed, respMsg)
+		}
+		if err == nil && t.verifiesSubject(subj) {
+			err = t.verifyMessage(subj, respMsg)
+		}
+		if err == nil {
+			call.resp = respMsg
+		}
 
 		if t.opts.Metrics != nil {
 			t.opts.Metrics.IncCounter("transport_requests_total")
@@ -60,7 +86,7 @@ func (t *Transport) RequestWithContext(
 		return err
 	}
 
-	return t.retry("Request", subject, traceID, req, base)
+	return t.retry(withOutbound(ctx, call), "Request", subject, traceID, req, base)
 }
 
 // ----------------------------------------------------
@@ -68,7 +94,7 @@ func (t *Transport) RequestWithContext(
 // ----------------------------------------------------
 
 func (t *Transport) Publish(subject string, data []byte) error {
-	if t.conn == nil {
+	if t.connection() == nil {
 		return ErrDisconnected
 	}
 
@@ -76,9 +102,26 @@ func (t *Transport) Publish(subject string, data []byte) error {
 	_ = codec.Unmarshal(data, msg)
 	setDefaultTrace(context.Background(), msg)
 	traceID := msg.GetString("trace_id")
+	if err := t.signMessage(msg); err != nil {
+		return err
+	}
+	if err := t.sealData(subject, msg); err != nil {
+		return err
+	}
 	data, _ = codec.Marshal(msg)
 
-	return t.retry("Publish", subject, traceID, data, t.conn.Publish)
+	ctx := withOutbound(context.Background(), &outboundCall{kind: RecordKindPublish})
+	return t.retry(ctx, "Publish", subject, traceID, data, t.publishConn)
+}
+
+// publishConn publishes on the current conn. It is looked up per attempt so
+// retries use a reconnected conn and a concurrent Close yields an error.
+func (t *Transport) publishConn(subject string, data []byte) error {
+	conn := t.connection()
+	if conn == nil {
+		return ErrDisconnected
+	}
+	return conn.Publish(subject, data)
 }
 
 // ----------------------------------------------------
@@ -86,6 +129,7 @@ func (t *Transport) Publish(subject string, data []byte) error {
 // ----------------------------------------------------
 
 func (t *Transport) retry(
+	ctx context.Context,
 	label string,
 	subject string,
 	traceID string,
@@ -100,11 +144,13 @@ func (t *Transport) retry(
 		policy.Delay = defaultRetryDelay
 	}
 
-	call := t.wrapChain(fn)
+	call := t.wrapChain(ctx, fn)
 	var lastErr error
 	delay := policy.Delay
+	attempts := 0
 
 	for attempt := 0; attempt <= policy.MaxAttempts; attempt++ {
+		attempts++
 		err := call(subject, data)
 		if err == nil {
 			if label == "Publish" && t.opts.Metrics != nil {
@@ -138,6 +184,7 @@ func (t *Transport) retry(
 	if t.opts.DeadLetterHandler != nil {
 		t.opts.DeadLetterHandler(subject, data, lastErr)
 	}
+	t.writeDeadLetter(newDeadLetter(subject, data, lastErr, attempts))
 	return lastErr
 }
 
@@ -146,7 +193,7 @@ func (t *Transport) retry(
 // ----------------------------------------------------
 
 func (t *Transport) Respond(replyTo string, msg codec.IMessage) error {
-	if t.conn == nil {
+	if t.connection() == nil {
 		return ErrDisconnected
 	}
 	setDefaultTrace(context.Background(), msg)
@@ -202,7 +249,7 @@ func (t *Transport) Broadcastf(format string, keys ...string) error {
 func setDefaultTrace(ctx context.Context, msg codec.IMessage) {
 	traceID := msg.GetString("trace_id")
 	if traceID == "" {
-		traceID = TraceIDFromContext(ctx)
+		traceID = contextTraceID(ctx)
 		if traceID == "" {
 			traceID = generateTraceID()
 		}
@@ -211,10 +258,15 @@ func setDefaultTrace(ctx context.Context, msg codec.IMessage) {
 	if msg.GetContextID() == "" {
 		msg.SetContextID(traceID)
 	}
+	ApplyCorrelation(ctx, msg)
+	if msg.GetHeader(constant.HeaderCorrelationID) == "" {
+		msg.SetHeader(constant.HeaderCorrelationID, traceID)
+	}
+	ApplyTraceContext(ctx, msg)
 }
 
 func generateTraceID() string {
-	return fmt.Sprintf("trace-%d", time.Now().UnixNano())
+	return newTraceID()
 }
 
 func WithTrace(ctx context.Context, traceID string) context.Context {
@@ -228,7 +280,7 @@ func TraceIDFromContext(ctx context.Context) string {
 	return ""
 }
 
-func (t *Transport) wrapChain(fn func(string, []byte) error) func(string, []byte) error {
+func (t *Transport) wrapChain(ctx context.Context, fn func(string, []byte) error) func(string, []byte) error {
 	return func(subject string, data []byte) error {
 		handler := func(ctx context.Context, subject string, data []byte) error {
 			return fn(subject, data)
@@ -236,8 +288,26 @@ func (t *Transport) wrapChain(fn func(string, []byte) error) func(string, []byte
 		for i := len(t.middlewares) - 1; i >= 0; i-- {
 			handler = t.middlewares[i](handler)
 		}
-		return handler(context.Background(), subject, data)
+		return handler(ctx, subject, data)
 	}
 }
 
 type traceKey struct{}
+
+// outboundCall describes the Request or Publish a middleware is running
+// for. It is absent on inbound messages.
+type outboundCall struct {
+	kind string         // RecordKindRequest or RecordKindPublish
+	resp codec.IMessage // reply of a successful request attempt
+}
+
+type outboundKey struct{}
+
+func withOutbound(ctx context.Context, call *outboundCall) context.Context {
+	return context.WithValue(ctx, outboundKey{}, call)
+}
+
+func outboundFrom(ctx context.Context) *outboundCall {
+	call, _ := ctx.Value(outboundKey{}).(*outboundCall)
+	return call
+}
@@ -144,7 +144,7 @@ func Test_wrapChain(t *testing.T) {
 	})
 
 	fn := func(subject string, data []byte) error { return nil }
-	wrapped := tr.wrapChain(fn)
+	wrapped := tr.wrapChain(context.Background(), fn)
 	err := wrapped("topic", []byte(`{}`))
 
 	assert.NoError(t, err)
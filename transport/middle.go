@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/logger"
 )
 
 // ----------------------------------------------------
@@ -20,14 +22,22 @@ type MiddlewareFunc func(next TransportHandler) TransportHandler
 // Trace middleware
 // ----------------------------------------------------
 
-// TraceMiddleware injects trace_id and context_id into the message and logs routing.
+// TraceMiddleware injects trace_id and context_id into the message and
+// stamps its traceparent.
 func TraceMiddleware() MiddlewareFunc {
+	return traceMiddleware(nil, nil)
+}
+
+// traceMiddleware is TraceMiddleware with routing logged at debug level to
+// log (when non-nil) and a producer span reported to spans.
+func traceMiddleware(log logger.ILogger, spans ISpanExporter) MiddlewareFunc {
 	return func(next TransportHandler) TransportHandler {
 		return func(ctx context.Context, subject string, data []byte) error {
 			msg := codec.NewMessage("")
 			if err := codec.Unmarshal(data, msg); err != nil {
 				return err
 			}
+			parent, _ := TraceParentFromContext(ctx)
 
 			// Ensure trace_id
 			traceID := msg.GetString("trace_id")
@@ -41,11 +51,43 @@ func TraceMiddleware() MiddlewareFunc {
 				msg.SetContextID(generateTraceID())
 			}
 
-			fmt.Printf("[trace] → %s (trace_id=%s, ctx_id=%s)\n",
-				subject, traceID, msg.GetContextID())
+			// Ensure correlation_id
+			if msg.GetHeader(constant.HeaderCorrelationID) == "" {
+				msg.SetHeader(constant.HeaderCorrelationID, traceID)
+			}
+
+			// Ensure traceparent and expose span/baggage to next
+			ApplyTraceContext(ctx, msg)
+			ctx = traceContextFromMessage(ctx, msg)
+
+			if log != nil {
+				log.Debug("[trace] → %s (trace_id=%s, ctx_id=%s)", subject, traceID, msg.GetContextID())
+			}
 
 			data, _ = codec.Marshal(msg)
-			return next(ctx, subject, data)
+			if spans == nil {
+				return next(ctx, subject, data)
+			}
+
+			span := Span{
+				Name:       "publish " + subject,
+				Kind:       SpanKindProducer,
+				Start:      time.Now(),
+				Attributes: map[string]string{"messaging.destination": subject, "trace_id": traceID},
+			}
+			if tp, ok := TraceParentFromContext(ctx); ok {
+				span.TraceID, span.SpanID = tp.TraceID, tp.SpanID
+			}
+			if parent.IsValid() && parent.TraceID == span.TraceID && parent.SpanID != span.SpanID {
+				span.ParentSpanID = parent.SpanID
+			}
+			err := next(ctx, subject, data)
+			span.End = time.Now()
+			if err != nil {
+				span.Err = err.Error()
+			}
+			spans.ExportSpan(span)
+			return err
 		}
 	}
 }
@@ -0,0 +1,341 @@
+// file: mini/transport/record.go
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/rskv-p/mini/logger"
+)
+
+// ----------------------------------------------------
+// Traffic recording
+// ----------------------------------------------------
+
+const (
+	RecordKindRequest = "request"
+	RecordKindPublish = "publish"
+
+	// HeaderReplayedFrom carries the original trace ID on replayed messages.
+	HeaderReplayedFrom = "replayed_from"
+)
+
+// Recording is one captured outbound message and, for requests, its reply.
+// Payloads are recorded as sent, with sensitive headers redacted.
+type Recording struct {
+	Kind     string            `json:"kind"`
+	Subject  string            `json:"subject"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Payload  json.RawMessage   `json:"payload"`
+	Response json.RawMessage   `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	At       time.Time         `json:"at"`
+	Offset   time.Duration     `json:"offset"` // since the recorder started
+	Duration time.Duration     `json:"duration"`
+}
+
+// Recorder writes sampled recordings as JSON lines.
+type Recorder struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	start  time.Time
+	sample float64
+	rnd    *rand.Rand
+}
+
+// NewRecorder records a sampleRate fraction (0..1] of traffic to w.
+// sampleRate <= 0 records everything.
+func NewRecorder(w io.Writer, sampleRate float64) *Recorder {
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &Recorder{
+		w:      w,
+		start:  time.Now(),
+		sample: sampleRate,
+		rnd:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NewFileRecorder records to path, appending if it exists.
+func NewFileRecorder(path string, sampleRate float64) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	// Recordings hold full payloads, so keep them private to the owner.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(f, sampleRate)
+	r.closer = f
+	return r, nil
+}
+
+// Sampled decides whether the next message is recorded.
+func (r *Recorder) Sampled() bool {
+	if r.sample >= 1 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Float64() < r.sample
+}
+
+// Record writes rec, filling At and Offset if unset.
+func (r *Recorder) Record(rec Recording) error {
+	if rec.At.IsZero() {
+		rec.At = time.Now()
+	}
+	if rec.Offset == 0 {
+		rec.Offset = rec.At.Sub(r.start)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying file, if the recorder owns one.
+func (r *Recorder) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Middleware records outbound requests and publishes as they go on the
+// wire, one recording per attempt; inbound traffic passes through. Sealed
+// messages are not recorded and sensitive headers are redacted.
+func (r *Recorder) Middleware() MiddlewareFunc {
+	return r.middleware(nil)
+}
+
+func (r *Recorder) middleware(log logger.ILogger) MiddlewareFunc {
+	return func(next TransportHandler) TransportHandler {
+		return func(ctx context.Context, subject string, data []byte) error {
+			call := outboundFrom(ctx)
+			if call == nil || !r.Sampled() {
+				return next(ctx, subject, data)
+			}
+			msg := recordable(data)
+			if msg == nil {
+				return next(ctx, subject, data)
+			}
+			payload, _ := codec.Marshal(msg)
+			rec := Recording{Kind: call.kind, Subject: subject, Headers: msg.GetHeaders(), Payload: payload, At: time.Now()}
+
+			err := next(ctx, subject, data)
+			rec.Duration = time.Since(rec.At)
+			if call.resp != nil {
+				raw, _ := codec.Marshal(call.resp)
+				if resp := recordable(raw); resp != nil {
+					rec.Response, _ = codec.Marshal(resp)
+				}
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			if werr := r.Record(rec); werr != nil && log != nil {
+				log.Warn("record %s: %v", subject, werr)
+			}
+			return err
+		}
+	}
+}
+
+// redactedHeaders are dropped from recordings: signatures, and anything
+// that looks like a credential.
+var redactedHeaders = []string{"auth", "token", "secret", "password", "cookie", "api_key", "apikey"}
+
+// recordable decodes data and redacts sensitive headers in the copy. It
+// returns nil for sealed or undecodable data.
+func recordable(data []byte) codec.IMessage {
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(data, msg); err != nil || IsSealed(msg) {
+		return nil
+	}
+	for k := range msg.GetHeaders() {
+		if sensitiveHeader(k) {
+			msg.SetHeader(k, "[redacted]")
+		}
+	}
+	return msg
+}
+
+func sensitiveHeader(name string) bool {
+	switch name {
+	case HeaderSig, HeaderSigKeyID, HeaderSigTime:
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, s := range redactedHeaders {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ----------------------------------------------------
+// Replay
+// ----------------------------------------------------
+
+// LoadRecordings reads JSON-line recordings from r.
+func LoadRecordings(r io.Reader) ([]Recording, error) {
+	var out []Recording
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec Recording
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, sc.Err()
+}
+
+// LoadRecordingFile reads recordings from path.
+func LoadRecordingFile(path string) ([]Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadRecordings(f)
+}
+
+// ReplayOptions controls Replay.
+type ReplayOptions struct {
+	// Speed scales recorded timing: 1 is original pace, 2 is twice as
+	// fast, 0 sends back-to-back.
+	Speed float64
+	// Subject rewrites the target subject; nil keeps the recorded one.
+	Subject func(string) string
+	// OnResult is called after each replayed message.
+	OnResult func(rec Recording, resp codec.IMessage, err error)
+}
+
+// ReplayReport summarizes a replay run.
+type ReplayReport struct {
+	Sent     int           `json:"sent"`
+	Failed   int           `json:"failed"`
+	Diverged int           `json:"diverged"` // responses whose result/error differ
+	Elapsed  time.Duration `json:"elapsed"`
+}
+
+// Replay re-issues recordings through tr. Each message gets a fresh trace;
+// the original trace ID is kept in the replayed_from header.
+func Replay(ctx context.Context, tr ITransport, recs []Recording, opts ReplayOptions) (ReplayReport, error) {
+	var report ReplayReport
+	start := time.Now()
+	var base time.Duration
+	if len(recs) > 0 {
+		base = recs[0].Offset
+	}
+
+	for _, rec := range recs {
+		if opts.Speed > 0 {
+			due := time.Duration(float64(rec.Offset-base) / opts.Speed)
+			if wait := due - time.Since(start); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					report.Elapsed = time.Since(start)
+					return report, ctx.Err()
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			report.Elapsed = time.Since(start)
+			return report, err
+		}
+
+		subject := rec.Subject
+		if opts.Subject != nil {
+			subject = opts.Subject(subject)
+		}
+		payload, err := replayPayload(rec.Payload)
+		if err != nil {
+			return report, err
+		}
+
+		var resp codec.IMessage
+		if rec.Kind == RecordKindPublish {
+			err = tr.Publish(subject, payload)
+		} else {
+			err = tr.RequestWithContext(ctx, subject, payload, func(m codec.IMessage) error {
+				resp = m
+				return nil
+			})
+		}
+
+		report.Sent++
+		if err != nil {
+			report.Failed++
+		} else if resp != nil && diverged(rec.Response, resp) {
+			report.Diverged++
+		}
+		if opts.OnResult != nil {
+			opts.OnResult(rec, resp, err)
+		}
+	}
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+// replayPayload strips trace identity and the reply subject so the
+// transport starts a new trace with a fresh reply subject.
+func replayPayload(raw []byte) ([]byte, error) {
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(raw, msg); err != nil {
+		return nil, err
+	}
+	if id := msg.GetString("trace_id"); id != "" {
+		msg.SetHeader(HeaderReplayedFrom, id)
+	}
+	delete(msg.GetBodyMap(), "trace_id")
+	delete(msg.GetHeaders(), constant.HeaderTraceparent)
+	msg.SetContextID("")
+	// The recorded reply subject belongs to the original request.
+	msg.SetReplyTo("")
+	return codec.Marshal(msg)
+}
+
+// diverged compares the result and error of a recorded and a live response.
+func diverged(recorded []byte, live codec.IMessage) bool {
+	if len(recorded) == 0 {
+		return false
+	}
+	old := codec.NewMessage("")
+	if err := codec.Unmarshal(recorded, old); err != nil {
+		return true
+	}
+	for _, key := range []string{constant.BodyKeyResult, constant.BodyKeyError} {
+		a, _ := json.Marshal(old.GetBodyMap()[key])
+		b, _ := json.Marshal(live.GetBodyMap()[key])
+		if string(a) != string(b) {
+			return true
+		}
+	}
+	return false
+}
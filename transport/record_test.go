@@ -0,0 +1,166 @@
+// file: mini/transport/record_test.go
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_CapturesTraffic(t *testing.T) {
+	var buf bytes.Buffer
+	tr := New(WithRecorder(NewRecorder(&buf, 1)))
+	tr.conn = &mockIConn{}
+
+	req := codec.NewRequest("user.get", "ctx-1")
+	req.Set("id", "42")
+	data, _ := codec.Marshal(req)
+	assert.NoError(t, tr.Request("users", data, nil))
+
+	ev := codec.NewMessage("event")
+	data, _ = codec.Marshal(ev)
+	assert.NoError(t, tr.Publish("events", data))
+
+	recs, err := LoadRecordings(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 2)
+
+	assert.Equal(t, RecordKindRequest, recs[0].Kind)
+	assert.Equal(t, "users", recs[0].Subject)
+	assert.Contains(t, string(recs[0].Payload), `"id":"42"`)
+	assert.Contains(t, string(recs[0].Response), `"ok":true`)
+	assert.NotEmpty(t, recs[0].Headers)
+
+	assert.Equal(t, RecordKindPublish, recs[1].Kind)
+	assert.Empty(t, recs[1].Response)
+}
+
+func TestRecorder_SkipsSealedAndRedacts(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+	s, _ := newTestSigner(t, "svc-a")
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	rec, err := NewFileRecorder(path, 1)
+	assert.NoError(t, err)
+
+	tr := New(WithRecorder(rec), WithSealing(k, "pay"), WithSigner(s))
+	tr.conn = &dlqConn{}
+
+	msg := codec.NewMessage("event")
+	msg.Set("card", "4111")
+	data, _ := codec.Marshal(msg)
+	assert.NoError(t, tr.Publish("pay", data))
+
+	msg = codec.NewMessage("event")
+	msg.SetHeader("Authorization", "Bearer secret")
+	msg.SetHeader("tenant", "acme")
+	data, _ = codec.Marshal(msg)
+	assert.NoError(t, tr.Publish("audit", data))
+	assert.NoError(t, rec.Close())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	recs, err := LoadRecordingFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 1)
+	assert.Equal(t, "audit", recs[0].Subject)
+	assert.Equal(t, "[redacted]", recs[0].Headers["Authorization"])
+	assert.Equal(t, "[redacted]", recs[0].Headers[HeaderSig])
+	assert.Equal(t, "acme", recs[0].Headers["tenant"])
+	assert.NotContains(t, string(recs[0].Payload), "Bearer secret")
+}
+
+func TestRecorder_Middleware_SkipsInbound(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewRecorder(&buf, 1).Middleware()(func(context.Context, string, []byte) error { return nil })
+	data, _ := codec.Marshal(codec.NewMessage("event"))
+	assert.NoError(t, h(context.Background(), "in", data))
+	assert.Zero(t, buf.Len())
+}
+
+func TestReplay(t *testing.T) {
+	recorded := codec.NewResponse("ctx", 200)
+	recorded.SetResult(true)
+	recordedResp, _ := codec.Marshal(recorded)
+
+	req := codec.NewRequest("user.get", "ctx-old")
+	req.Set("trace_id", "old-trace")
+	payload, _ := codec.Marshal(req)
+
+	recs := []Recording{
+		{Kind: RecordKindRequest, Subject: "users", Payload: payload, Response: recordedResp, Offset: 0},
+		{Kind: RecordKindPublish, Subject: "events", Payload: payload, Offset: 20 * time.Millisecond},
+	}
+
+	conn := &dlqConn{}
+	tr := New()
+	tr.conn = conn
+
+	var subjects []string
+	start := time.Now()
+	report, err := Replay(context.Background(), tr, recs, ReplayOptions{
+		Speed:   2,
+		Subject: func(s string) string { return "staging." + s },
+		OnResult: func(rec Recording, resp codec.IMessage, err error) {
+			subjects = append(subjects, rec.Subject)
+		},
+	})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, 2, report.Sent)
+	assert.Equal(t, 0, report.Failed)
+	// mockIConn answers {"ok":true} without a result
+	assert.Equal(t, 1, report.Diverged)
+	assert.Equal(t, []string{"users", "events"}, subjects)
+
+	out := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(conn.published["staging.events"], out))
+	assert.Equal(t, "old-trace", out.GetHeader(HeaderReplayedFrom))
+	assert.NotEqual(t, "old-trace", out.GetString("trace_id"))
+}
+
+// replyConn fails a request whose reply subject is already in use, like
+// Conn does while the earlier reply subscription is alive.
+type replyConn struct {
+	mockIConn
+	replies map[string]bool
+}
+
+func (c *replyConn) Request(subject string, data []byte, timeout time.Duration) (codec.IMessage, error) {
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	if c.replies[msg.GetReplyTo()] {
+		return nil, fmt.Errorf("consumer for subject %s already exists", msg.GetReplyTo())
+	}
+	c.replies[msg.GetReplyTo()] = true
+	return c.mockIConn.Request(subject, data, timeout)
+}
+
+func TestReplay_FreshReplySubject(t *testing.T) {
+	req := codec.NewRequest("user.get", "ctx-old")
+	req.SetReplyTo("reply.ctx-old")
+	payload, _ := codec.Marshal(req)
+	recs := []Recording{{Kind: RecordKindRequest, Subject: "users", Payload: payload}}
+
+	conn := &replyConn{replies: map[string]bool{}}
+	tr := New()
+	tr.conn = conn
+
+	for i := 0; i < 2; i++ {
+		report, err := Replay(context.Background(), tr, recs, ReplayOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Failed)
+	}
+	assert.Len(t, conn.replies, 2)
+	assert.NotContains(t, conn.replies, "reply.ctx-old")
+}
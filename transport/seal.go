@@ -0,0 +1,340 @@
+// file: mini/transport/seal.go
+package transport
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+)
+
+// ----------------------------------------------------
+// Payload sealing (AES-GCM with rotating shared keys)
+// ----------------------------------------------------
+//
+// A sealed message keeps its routing fields (type, node, contextID, replyTo,
+// headers) and trace_id in plaintext; every other body field is encrypted
+// into a single "sealed" field. The key ID and algorithm travel as headers.
+
+const (
+	HeaderSealKeyID = "seal_kid"
+	HeaderSealAlg   = "seal_alg"
+	SealAlgAESGCM   = "AES-GCM"
+
+	sealedBodyKey = "sealed"
+)
+
+var (
+	ErrUnsealFailed    = errors.New("transport: unseal failed")
+	ErrInvalidSealKey  = errors.New("transport: seal key must be 16, 24 or 32 bytes")
+	ErrNoActiveSealKey = errors.New("transport: no active seal key")
+	ErrNotSealed       = errors.New("transport: plaintext message on sealed subject")
+)
+
+// SealError is a structured decryption failure.
+type SealError struct {
+	KeyID  string
+	Reason string
+}
+
+func (e *SealError) Error() string {
+	return fmt.Sprintf("transport: unseal failed (kid=%s): %s", e.KeyID, e.Reason)
+}
+
+func (e *SealError) Unwrap() error { return ErrUnsealFailed }
+
+// Keyring holds shared AES keys by ID. New messages are sealed with the
+// active key; any known key can open. Rotate keeps old keys for draining.
+type Keyring struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring with one active key.
+func NewKeyring(id string, key []byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	if err := k.Rotate(id, key); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Add registers a key that can open messages but is not used for sealing.
+func (k *Keyring) Add(id string, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = aead
+	return nil
+}
+
+// Rotate adds a key and makes it the active sealing key.
+func (k *Keyring) Rotate(id string, key []byte) error {
+	if err := k.Add(id, key); err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active = id
+	return nil
+}
+
+// Remove drops a key. Removing the active key disables sealing.
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+	if k.active == id {
+		k.active = ""
+	}
+}
+
+// ActiveKeyID returns the ID of the sealing key.
+func (k *Keyring) ActiveKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Seal encrypts the message body in place.
+func (k *Keyring) Seal(msg codec.IMessage) error {
+	if IsSealed(msg) {
+		return nil
+	}
+
+	k.mu.RLock()
+	id := k.active
+	aead := k.keys[id]
+	k.mu.RUnlock()
+	if aead == nil {
+		return ErrNoActiveSealKey
+	}
+
+	body := msg.GetBodyMap()
+	traceID, hasTrace := body["trace_id"]
+	plain := make(map[string]any, len(body))
+	for key, v := range body {
+		if key != "trace_id" {
+			plain[key] = v
+		}
+	}
+	raw, err := json.Marshal(plain)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nonce, nonce, raw, sealAAD(id, msg))
+
+	msg.SetBody(nil)
+	msg.Set(sealedBodyKey, base64.StdEncoding.EncodeToString(sealed))
+	if hasTrace {
+		msg.Set("trace_id", traceID)
+	}
+	msg.SetHeader(HeaderSealKeyID, id)
+	msg.SetHeader(HeaderSealAlg, SealAlgAESGCM)
+	return nil
+}
+
+// Open decrypts a sealed message body in place. Plain messages are left as is.
+func (k *Keyring) Open(msg codec.IMessage) error {
+	if !IsSealed(msg) {
+		return nil
+	}
+	id := msg.GetHeader(HeaderSealKeyID)
+	if alg := msg.GetHeader(HeaderSealAlg); alg != SealAlgAESGCM {
+		return &SealError{KeyID: id, Reason: "unsupported algorithm " + alg}
+	}
+
+	k.mu.RLock()
+	aead := k.keys[id]
+	k.mu.RUnlock()
+	if aead == nil {
+		return &SealError{KeyID: id, Reason: "unknown key"}
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(msg.GetString(sealedBodyKey))
+	if err != nil {
+		return &SealError{KeyID: id, Reason: "invalid encoding"}
+	}
+	if len(sealed) < aead.NonceSize() {
+		return &SealError{KeyID: id, Reason: "ciphertext too short"}
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	raw, err := aead.Open(nil, nonce, ciphertext, sealAAD(id, msg))
+	if err != nil {
+		return &SealError{KeyID: id, Reason: "authentication failed"}
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return &SealError{KeyID: id, Reason: "invalid payload"}
+	}
+	if traceID, ok := msg.Get("trace_id"); ok {
+		body["trace_id"] = traceID
+	}
+	msg.SetBody(body)
+	delete(msg.GetHeaders(), HeaderSealKeyID)
+	delete(msg.GetHeaders(), HeaderSealAlg)
+	return nil
+}
+
+// IsSealed reports whether msg carries a sealed body.
+func IsSealed(msg codec.IMessage) bool {
+	return msg.GetHeader(HeaderSealKeyID) != ""
+}
+
+// ----------------------------------------------------
+// Transport integration
+// ----------------------------------------------------
+
+// sealData seals an encoded message if sealing applies to subject or the
+// subject is the reply address of a sealed request.
+func (t *Transport) sealData(subject string, msg codec.IMessage) error {
+	if !t.sealsSubject(subject) && !t.takeSealedReply(subject) {
+		return nil
+	}
+	if err := t.opts.Keyring.Seal(msg); err != nil {
+		return err
+	}
+	if t.opts.Metrics != nil {
+		t.opts.Metrics.IncCounter("transport_sealed_total")
+	}
+	return nil
+}
+
+// openData opens raw message bytes received on subject. Plaintext is only
+// accepted on subjects that are not sealed.
+func (t *Transport) openData(subject string, data []byte) ([]byte, error) {
+	if t.opts.Keyring == nil {
+		return data, nil
+	}
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(data, msg); err != nil || !IsSealed(msg) {
+		if t.sealsSubject(subject) {
+			return nil, t.rejectPlain(subject)
+		}
+		return data, nil
+	}
+	if err := t.openMessage(msg); err != nil {
+		return nil, err
+	}
+	if msg.GetReplyTo() != "" {
+		t.expectSealedReply(msg.GetReplyTo())
+	}
+	return codec.Marshal(msg)
+}
+
+// openMessage opens a decoded message and records failures.
+func (t *Transport) openMessage(msg codec.IMessage) error {
+	if t.opts.Keyring == nil {
+		return nil
+	}
+	err := t.opts.Keyring.Open(msg)
+	if err != nil {
+		if t.opts.Metrics != nil {
+			t.opts.Metrics.IncCounter("transport_unseal_failed")
+		}
+		if t.opts.Logger != nil {
+			t.opts.Logger.Warn("%v", err)
+		}
+	}
+	return err
+}
+
+// openReply opens the reply to a request. Replies to sealed requests must
+// be sealed as well.
+func (t *Transport) openReply(subject string, sealed bool, msg codec.IMessage) error {
+	if sealed && !IsSealed(msg) {
+		return t.rejectPlain(subject)
+	}
+	return t.openMessage(msg)
+}
+
+func (t *Transport) rejectPlain(subject string) error {
+	err := fmt.Errorf("%w: %s", ErrNotSealed, subject)
+	if t.opts.Metrics != nil {
+		t.opts.Metrics.IncCounter("transport_unseal_failed")
+	}
+	if t.opts.Logger != nil {
+		t.opts.Logger.Warn("%v", err)
+	}
+	return err
+}
+
+// expectSealedReply remembers that replies to replyTo must be sealed. Entries
+// expire after the request timeout so unanswered requests do not pile up.
+func (t *Transport) expectSealedReply(replyTo string) {
+	timeout := t.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	now := time.Now()
+
+	t.sealMu.Lock()
+	defer t.sealMu.Unlock()
+	if t.sealedReplies == nil {
+		t.sealedReplies = make(map[string]time.Time)
+	}
+	for subj, exp := range t.sealedReplies {
+		if now.After(exp) {
+			delete(t.sealedReplies, subj)
+		}
+	}
+	t.sealedReplies[replyTo] = now.Add(timeout)
+}
+
+// takeSealedReply reports whether subject answers a sealed request and
+// forgets it.
+func (t *Transport) takeSealedReply(subject string) bool {
+	if t.opts.Keyring == nil {
+		return false
+	}
+	t.sealMu.Lock()
+	defer t.sealMu.Unlock()
+	exp, ok := t.sealedReplies[subject]
+	if ok {
+		delete(t.sealedReplies, subject)
+	}
+	return ok && !time.Now().After(exp)
+}
+
+func (t *Transport) sealsSubject(subject string) bool {
+	if t.opts.Keyring == nil {
+		return false
+	}
+	return matchSubjects(t.opts.SealSubjects, subject, true)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidSealKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealAAD binds the ciphertext to its key ID and routing fields.
+func sealAAD(id string, msg codec.IMessage) []byte {
+	return []byte(id + "|" + msg.GetType() + "|" + msg.GetNode())
+}
@@ -0,0 +1,162 @@
+// file: mini/transport/seal_test.go
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, 32)
+	key2 = bytes.Repeat([]byte{2}, 16)
+)
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring("k1", key1)
+	assert.NoError(t, err)
+
+	msg := codec.NewRequest("user.get", "ctx-1")
+	msg.Set("password", "secret")
+	msg.Set("trace_id", "trace-1")
+
+	assert.NoError(t, k.Seal(msg))
+	assert.True(t, IsSealed(msg))
+	assert.Equal(t, "", msg.GetString("password"))
+	assert.Equal(t, "trace-1", msg.GetString("trace_id"))
+	assert.Equal(t, "k1", msg.GetHeader(HeaderSealKeyID))
+
+	assert.NoError(t, k.Open(msg))
+	assert.False(t, IsSealed(msg))
+	assert.Equal(t, "secret", msg.GetString("password"))
+	assert.Equal(t, "trace-1", msg.GetString("trace_id"))
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+
+	old := codec.NewMessage("event")
+	old.Set("v", 1)
+	assert.NoError(t, k.Seal(old))
+
+	assert.NoError(t, k.Rotate("k2", key2))
+	assert.Equal(t, "k2", k.ActiveKeyID())
+
+	fresh := codec.NewMessage("event")
+	fresh.Set("v", 2)
+	assert.NoError(t, k.Seal(fresh))
+	assert.Equal(t, "k2", fresh.GetHeader(HeaderSealKeyID))
+
+	assert.NoError(t, k.Open(old))
+	assert.NoError(t, k.Open(fresh))
+
+	k.Remove("k2")
+	assert.ErrorIs(t, k.Seal(codec.NewMessage("event")), ErrNoActiveSealKey)
+}
+
+func TestKeyring_Failures(t *testing.T) {
+	_, err := NewKeyring("bad", []byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidSealKey)
+
+	k, _ := NewKeyring("k1", key1)
+	other, _ := NewKeyring("k9", key2)
+
+	msg := codec.NewMessage("event")
+	msg.Set("x", "y")
+	assert.NoError(t, other.Seal(msg))
+
+	err = k.Open(msg)
+	var sealErr *SealError
+	assert.True(t, errors.As(err, &sealErr))
+	assert.Equal(t, "k9", sealErr.KeyID)
+	assert.ErrorIs(t, err, ErrUnsealFailed)
+
+	tampered := codec.NewRequest("a.b", "ctx")
+	tampered.Set("x", "y")
+	assert.NoError(t, k.Seal(tampered))
+	tampered.SetNode("c.d")
+	err = k.Open(tampered)
+	assert.ErrorAs(t, err, &sealErr)
+	assert.Equal(t, "authentication failed", sealErr.Reason)
+}
+
+func TestTransport_SealedPublish(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+	conn := &dlqConn{}
+	tr := New(WithSealing(k, "secure"))
+	tr.conn = conn
+
+	msg := codec.NewMessage("event")
+	msg.Set("card", "4111")
+	data, _ := codec.Marshal(msg)
+
+	assert.NoError(t, tr.Publish("secure", data))
+	assert.NoError(t, tr.Publish("plain", data))
+
+	assert.NotContains(t, string(conn.published["secure"]), "4111")
+	assert.Contains(t, string(conn.published["plain"]), "4111")
+
+	opened, err := tr.openData("secure", conn.published["secure"])
+	assert.NoError(t, err)
+	out := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(opened, out))
+	assert.Equal(t, "4111", out.GetString("card"))
+}
+
+func TestTransport_RejectsPlainOnSealedSubject(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+	tr := New(WithSealing(k, "secure"))
+
+	msg := codec.NewMessage("event")
+	msg.Set("card", "4111")
+	data, _ := codec.Marshal(msg)
+
+	_, err := tr.openData("secure", data)
+	assert.ErrorIs(t, err, ErrNotSealed)
+
+	out, err := tr.openData("plain", data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, out)
+}
+
+func TestTransport_SealsReplyToSealedRequest(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+	conn := &dlqConn{}
+	tr := New(WithSealing(k, "secure"))
+	tr.conn = conn
+
+	req := codec.NewRequest("secure", "ctx-1")
+	req.SetReplyTo("reply.ctx-1")
+	req.Set("card", "4111")
+	assert.NoError(t, k.Seal(req))
+	data, _ := codec.Marshal(req)
+
+	_, err := tr.openData("secure", data)
+	assert.NoError(t, err)
+
+	resp := codec.NewMessage("response")
+	resp.Set("balance", "100")
+	assert.NoError(t, tr.Respond("reply.ctx-1", resp))
+	assert.NotContains(t, string(conn.published["reply.ctx-1"]), "100")
+
+	// The reply address is used once.
+	assert.NoError(t, tr.Respond("reply.ctx-1", resp))
+	assert.Contains(t, string(conn.published["reply.ctx-1"]), "100")
+}
+
+func TestTransport_RejectsPlainReplyToSealedRequest(t *testing.T) {
+	k, _ := NewKeyring("k1", key1)
+	tr := New(WithSealing(k, "secure"))
+	tr.conn = &mockIConn{} // replies in plaintext
+	tr.opts.Timeout = time.Second
+
+	data, _ := codec.Marshal(codec.NewMessage("request"))
+	err := tr.Request("secure", data, nil)
+	assert.ErrorIs(t, err, ErrNotSealed)
+
+	assert.NoError(t, tr.Request("plain", data, nil))
+}
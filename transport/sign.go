@@ -0,0 +1,256 @@
+// file: mini/transport/sign.go
+package transport
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+)
+
+// ----------------------------------------------------
+// Message signing (Ed25519)
+// ----------------------------------------------------
+//
+// The signature covers type, node, contextID, replyTo, the signing time and
+// the JSON-encoded body (map keys sorted). Other headers are excluded
+// because later layers (sealing, tracing) add them after signing. The
+// signing time bounds how long a captured message can be replayed.
+
+const (
+	HeaderSigKeyID = "sig_kid"
+	HeaderSig      = "sig"
+	HeaderSigTime  = "sig_ts" // unix nanoseconds
+
+	// DefaultMaxSkew is how far a signing time may be from the local clock.
+	DefaultMaxSkew = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSignature = errors.New("transport: invalid signature")
+	ErrInvalidSignKey   = errors.New("transport: invalid ed25519 key")
+)
+
+// SignatureError is a structured verification failure.
+type SignatureError struct {
+	KeyID  string
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("transport: signature rejected (kid=%s): %s", e.KeyID, e.Reason)
+}
+
+func (e *SignatureError) Unwrap() error { return ErrInvalidSignature }
+
+// Signer signs outgoing messages with a publisher's private key.
+type Signer struct {
+	id  string
+	key ed25519.PrivateKey
+	now func() time.Time
+}
+
+// NewSigner returns a signer identified by id.
+func NewSigner(id string, key ed25519.PrivateKey) (*Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidSignKey
+	}
+	return &Signer{id: id, key: key, now: time.Now}, nil
+}
+
+// ID returns the publisher key ID.
+func (s *Signer) ID() string { return s.id }
+
+// Sign adds sig_kid, sig_ts and sig headers to msg.
+func (s *Signer) Sign(msg codec.IMessage) error {
+	msg.SetHeader(HeaderSigTime, strconv.FormatInt(s.now().UnixNano(), 10))
+	payload, err := canonicalize(msg)
+	if err != nil {
+		return err
+	}
+	msg.SetHeader(HeaderSigKeyID, s.id)
+	msg.SetHeader(HeaderSig, base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)))
+	return nil
+}
+
+// KeySet holds trusted publisher public keys by ID.
+type KeySet struct {
+	mu      sync.RWMutex
+	keys    map[string]ed25519.PublicKey
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+// NewKeySet returns an empty key set that accepts signing times within
+// DefaultMaxSkew of the local clock.
+func NewKeySet() *KeySet {
+	return &KeySet{
+		keys:    make(map[string]ed25519.PublicKey),
+		maxSkew: DefaultMaxSkew,
+		now:     time.Now,
+	}
+}
+
+// SetMaxSkew changes the accepted distance between the signing time and
+// the local clock. Zero restores DefaultMaxSkew.
+func (k *KeySet) SetMaxSkew(d time.Duration) {
+	if d <= 0 {
+		d = DefaultMaxSkew
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.maxSkew = d
+}
+
+// KeySetFromMap builds a key set from base64-encoded public keys,
+// e.g. a "signing_keys" config section.
+func KeySetFromMap(keys map[string]string) (*KeySet, error) {
+	ks := NewKeySet()
+	for id, enc := range keys {
+		raw, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		if err := ks.Add(id, raw); err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+	}
+	return ks, nil
+}
+
+// Add trusts a public key.
+func (k *KeySet) Add(id string, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return ErrInvalidSignKey
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+	return nil
+}
+
+// Remove revokes a public key.
+func (k *KeySet) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+}
+
+// Verify checks the message signature against the trusted keys.
+func (k *KeySet) Verify(msg codec.IMessage) error {
+	id := msg.GetHeader(HeaderSigKeyID)
+	sig := msg.GetHeader(HeaderSig)
+	if id == "" || sig == "" {
+		return &SignatureError{Reason: "unsigned message"}
+	}
+
+	k.mu.RLock()
+	pub := k.keys[id]
+	maxSkew := k.maxSkew
+	k.mu.RUnlock()
+	if pub == nil {
+		return &SignatureError{KeyID: id, Reason: "unknown publisher"}
+	}
+
+	ts, err := strconv.ParseInt(msg.GetHeader(HeaderSigTime), 10, 64)
+	if err != nil {
+		return &SignatureError{KeyID: id, Reason: "missing signing time"}
+	}
+	if skew := k.now().Sub(time.Unix(0, ts)); skew > maxSkew || skew < -maxSkew {
+		return &SignatureError{KeyID: id, Reason: "signing time outside allowed skew"}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return &SignatureError{KeyID: id, Reason: "invalid encoding"}
+	}
+	payload, err := canonicalize(msg)
+	if err != nil {
+		return &SignatureError{KeyID: id, Reason: "invalid payload"}
+	}
+	if !ed25519.Verify(pub, payload, raw) {
+		return &SignatureError{KeyID: id, Reason: "bad signature"}
+	}
+	return nil
+}
+
+// canonicalize returns the signed byte representation of msg.
+func canonicalize(msg codec.IMessage) ([]byte, error) {
+	body, err := json.Marshal(msg.GetBodyMap())
+	if err != nil {
+		return nil, err
+	}
+	head := strings.Join([]string{
+		msg.GetType(), msg.GetNode(), msg.GetContextID(), msg.GetReplyTo(), msg.GetHeader(HeaderSigTime),
+	}, "\n") + "\n"
+	return append([]byte(head), body...), nil
+}
+
+// ----------------------------------------------------
+// Transport integration
+// ----------------------------------------------------
+
+// signMessage signs an outgoing message if a signer is configured.
+func (t *Transport) signMessage(msg codec.IMessage) error {
+	if t.opts.Signer == nil {
+		return nil
+	}
+	return t.opts.Signer.Sign(msg)
+}
+
+// verifyData rejects unsigned or invalid messages on verified subjects.
+func (t *Transport) verifyData(subject string, data []byte) error {
+	if !t.verifiesSubject(subject) {
+		return nil
+	}
+	msg := codec.NewMessage("")
+	if err := codec.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	return t.verifyMessage(subject, msg)
+}
+
+// verifiesSubject reports whether messages on subject, and replies to
+// requests sent to it, must be signed.
+func (t *Transport) verifiesSubject(subject string) bool {
+	return t.opts.Verifier != nil && matchSubjects(t.opts.VerifySubjects, subject, true)
+}
+
+// verifyMessage checks a decoded message and records failures.
+func (t *Transport) verifyMessage(subject string, msg codec.IMessage) error {
+	if err := t.opts.Verifier.Verify(msg); err != nil {
+		if t.opts.Metrics != nil {
+			t.opts.Metrics.IncCounter("transport_signature_rejected")
+		}
+		if t.opts.Logger != nil {
+			t.opts.Logger.Warn("%s: %v", subject, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// matchSubjects reports whether subject matches any pattern.
+// A trailing "*" matches any suffix (e.g. "runn.*"). An empty pattern
+// list matches everything when emptyMatchesAll is set.
+func matchSubjects(patterns []string, subject string, emptyMatchesAll bool) bool {
+	if len(patterns) == 0 {
+		return emptyMatchesAll
+	}
+	for _, p := range patterns {
+		if p == subject {
+			return true
+		}
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(subject, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// file: mini/transport/sign_test.go
+package transport
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSigner(t *testing.T, id string) (*Signer, ed25519.PublicKey) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	s, err := NewSigner(id, priv)
+	assert.NoError(t, err)
+	return s, pub
+}
+
+func TestSigner_SignVerify(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks := NewKeySet()
+	assert.NoError(t, ks.Add("svc-a", pub))
+
+	msg := codec.NewRequest("cfg.set", "ctx-1")
+	msg.Set("key", "value")
+	assert.NoError(t, s.Sign(msg))
+	assert.Equal(t, "svc-a", msg.GetHeader(HeaderSigKeyID))
+	assert.NoError(t, ks.Verify(msg))
+
+	msg.Set("key", "tampered")
+	err := ks.Verify(msg)
+	var sigErr *SignatureError
+	assert.True(t, errors.As(err, &sigErr))
+	assert.Equal(t, "bad signature", sigErr.Reason)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestKeySet_Rejects(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks, err := KeySetFromMap(map[string]string{"svc-a": base64.StdEncoding.EncodeToString(pub)})
+	assert.NoError(t, err)
+
+	var sigErr *SignatureError
+	assert.ErrorAs(t, ks.Verify(codec.NewMessage("event")), &sigErr)
+	assert.Equal(t, "unsigned message", sigErr.Reason)
+
+	msg := codec.NewMessage("event")
+	assert.NoError(t, s.Sign(msg))
+	ks.Remove("svc-a")
+	assert.ErrorAs(t, ks.Verify(msg), &sigErr)
+	assert.Equal(t, "unknown publisher", sigErr.Reason)
+
+	_, err = NewSigner("bad", []byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidSignKey)
+}
+
+func TestTransport_VerifySubjects(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks := NewKeySet()
+	_ = ks.Add("svc-a", pub)
+	tr := New(WithVerification(ks, "cfg.*"))
+
+	unsigned, _ := codec.Marshal(codec.NewMessage("event"))
+	assert.ErrorIs(t, tr.verifyData("cfg.set", unsigned), ErrInvalidSignature)
+	assert.NoError(t, tr.verifyData("orders.created", unsigned))
+
+	msg := codec.NewMessage("event")
+	msg.Set("v", 1)
+	assert.NoError(t, s.Sign(msg))
+	signed, _ := codec.Marshal(msg)
+	assert.NoError(t, tr.verifyData("cfg.set", signed))
+}
+
+func TestTransport_SignedSealedPublish(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks := NewKeySet()
+	_ = ks.Add("svc-a", pub)
+	k, _ := NewKeyring("k1", key1)
+
+	conn := &dlqConn{}
+	tr := New(WithSigner(s), WithSealing(k), WithVerification(ks))
+	tr.conn = conn
+
+	msg := codec.NewMessage("event")
+	msg.Set("card", "4111")
+	data, _ := codec.Marshal(msg)
+	assert.NoError(t, tr.Publish("secure", data))
+
+	opened, err := tr.openData("secure", conn.published["secure"])
+	assert.NoError(t, err)
+	assert.NoError(t, tr.verifyData("secure", opened))
+}
+
+func TestKeySet_RejectsReplayAndRedirect(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks := NewKeySet()
+	_ = ks.Add("svc-a", pub)
+
+	var sigErr *SignatureError
+	msg := codec.NewRequest("cfg.set", "ctx-1")
+	msg.SetReplyTo("reply.ctx-1")
+	assert.NoError(t, s.Sign(msg))
+	msg.SetReplyTo("reply.attacker")
+	assert.ErrorAs(t, ks.Verify(msg), &sigErr)
+	assert.Equal(t, "bad signature", sigErr.Reason)
+
+	s.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	old := codec.NewMessage("event")
+	assert.NoError(t, s.Sign(old))
+	assert.ErrorAs(t, ks.Verify(old), &sigErr)
+	assert.Equal(t, "signing time outside allowed skew", sigErr.Reason)
+
+	ks.SetMaxSkew(2 * time.Hour)
+	assert.NoError(t, ks.Verify(old))
+
+	old.SetHeader(HeaderSigTime, "")
+	assert.ErrorAs(t, ks.Verify(old), &sigErr)
+	assert.Equal(t, "missing signing time", sigErr.Reason)
+}
+
+func TestRequestWithContext_VerifiesResponse(t *testing.T) {
+	s, pub := newTestSigner(t, "svc-a")
+	ks := NewKeySet()
+	_ = ks.Add("svc-a", pub)
+
+	// mockIConn answers with an unsigned message.
+	tr := New(WithSigner(s), WithVerification(ks, "cfg.*"))
+	tr.conn = &mockIConn{}
+	tr.opts.Timeout = time.Second
+
+	data, _ := codec.Marshal(codec.NewRequest("cfg.get", "ctx-1"))
+	called := false
+	err := tr.RequestWithContext(context.Background(), "cfg.get", data, func(codec.IMessage) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.False(t, called)
+
+	err = tr.RequestWithContext(context.Background(), "orders.get", data, func(codec.IMessage) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
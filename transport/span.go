@@ -0,0 +1,47 @@
+// file: mini/transport/span.go
+package transport
+
+import (
+	"time"
+)
+
+// ----------------------------------------------------
+// Spans
+// ----------------------------------------------------
+//
+// The transport does not link the OpenTelemetry SDK. Instead every
+// outbound message is recorded as a producer Span carrying the same IDs as
+// its traceparent header, and handed to an ISpanExporter. An adapter that
+// forwards spans to an OTel exporter joins them with the peers' traces.
+
+// SpanKindProducer marks a span for an outbound publish or request.
+const SpanKindProducer = "producer"
+
+// Span is a finished unit of work in a trace.
+type Span struct {
+	Name         string            `json:"name"`
+	Kind         string            `json:"kind"`
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Err          string            `json:"error,omitempty"`
+}
+
+// Duration returns how long the span took.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// ISpanExporter receives finished spans. ExportSpan is called on the
+// publishing goroutine and should not block.
+type ISpanExporter interface {
+	ExportSpan(Span)
+}
+
+// SpanExporterFunc adapts a function to ISpanExporter.
+type SpanExporterFunc func(Span)
+
+func (f SpanExporterFunc) ExportSpan(s Span) { f(s) }
@@ -0,0 +1,257 @@
+// file: mini/transport/subscriptions.go
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNotSubscribed = errors.New("transport: topic not subscribed")
+	ErrTopicPaused   = errors.New("transport: topic paused")
+	ErrDrainTimeout  = errors.New("transport: drain timed out")
+)
+
+// defaultMaxInFlight matches nsq.NewConfig and is restored on resume.
+const defaultMaxInFlight = 1
+
+// SubscriptionInfo is a point-in-time view of one topic subscription.
+type SubscriptionInfo struct {
+	Topic     string    `json:"topic"`
+	Channel   string    `json:"channel"`
+	Paused    bool      `json:"paused"`
+	InFlight  int64     `json:"in_flight"`
+	Delivered int64     `json:"delivered"`
+	Failed    int64     `json:"failed"`
+	Requeued  int64     `json:"requeued"`
+	Since     time.Time `json:"since"`
+}
+
+// topicSub tracks a live subscription and its handler activity.
+type topicSub struct {
+	sub   *Subscription
+	since time.Time
+	// mu orders admission against pausing: once setPaused(true) returns,
+	// every admitted handler is counted in active and no more are admitted,
+	// so active.Wait cannot miss one.
+	mu        sync.Mutex
+	paused    atomic.Bool
+	inFlight  atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+	requeued  atomic.Int64
+	active    sync.WaitGroup
+}
+
+// gate wraps a conn-level handler with pause and accounting. While paused
+// messages are rejected with ErrTopicPaused so NSQ requeues them.
+func (s *topicSub) gate(next MsgHandler) MsgHandler {
+	return func(data []byte) error {
+		s.mu.Lock()
+		if s.paused.Load() {
+			s.mu.Unlock()
+			s.requeued.Add(1)
+			return ErrTopicPaused
+		}
+		s.active.Add(1)
+		s.inFlight.Add(1)
+		s.mu.Unlock()
+		defer func() {
+			s.inFlight.Add(-1)
+			s.active.Done()
+		}()
+		err := next(data)
+		s.delivered.Add(1)
+		if err != nil {
+			s.failed.Add(1)
+		}
+		return err
+	}
+}
+
+// setPaused changes the paused state and reports whether it changed.
+func (s *topicSub) setPaused(paused bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused.CompareAndSwap(!paused, paused)
+}
+
+func (s *topicSub) info(topic string) SubscriptionInfo {
+	info := SubscriptionInfo{
+		Topic:     topic,
+		Paused:    s.paused.Load(),
+		InFlight:  s.inFlight.Load(),
+		Delivered: s.delivered.Load(),
+		Failed:    s.failed.Load(),
+		Requeued:  s.requeued.Load(),
+		Since:     s.since,
+	}
+	if s.sub != nil {
+		info.Channel = s.sub.channel
+	}
+	return info
+}
+
+// setInFlight throttles the underlying consumer, if any. Zero stops NSQ
+// from pushing new messages while keeping the connection open.
+func (s *topicSub) setInFlight(n int) {
+	if s.sub != nil && s.sub.consumer != nil {
+		s.sub.consumer.ChangeMaxInFlight(n)
+	}
+}
+
+// ----------------------------------------------------
+// Registration
+// ----------------------------------------------------
+
+// subscribeTracked subscribes handler to topic through the conn and records
+// it for ListSubscriptions. Caller must hold t.mu.
+func (t *Transport) subscribeTracked(topic string, handler MsgHandler) (*Subscription, error) {
+	entry := &topicSub{since: time.Now()}
+	sub, err := t.conn.Subscribe(topic, entry.gate(handler))
+	if err != nil {
+		return nil, err
+	}
+	entry.sub = sub
+
+	t.subsMu.Lock()
+	if t.subs == nil {
+		t.subs = make(map[string]*topicSub)
+	}
+	t.subs[topic] = entry
+	t.subsMu.Unlock()
+	return sub, nil
+}
+
+func (t *Transport) lookupSub(topic string) (*topicSub, error) {
+	t.subsMu.RLock()
+	defer t.subsMu.RUnlock()
+	entry, ok := t.subs[topic]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotSubscribed, topic)
+	}
+	return entry, nil
+}
+
+func (t *Transport) forgetSub(topic string) {
+	t.subsMu.Lock()
+	delete(t.subs, topic)
+	t.subsMu.Unlock()
+}
+
+// ----------------------------------------------------
+// Operational control
+// ----------------------------------------------------
+
+// ListSubscriptions returns all active topic subscriptions sorted by topic.
+func (t *Transport) ListSubscriptions() []SubscriptionInfo {
+	t.subsMu.RLock()
+	defer t.subsMu.RUnlock()
+
+	out := make([]SubscriptionInfo, 0, len(t.subs))
+	for topic, entry := range t.subs {
+		out = append(out, entry.info(topic))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Topic < out[j].Topic })
+	return out
+}
+
+// PauseTopic stops pulling new messages for topic without closing the
+// consumer. Messages that still arrive are requeued.
+func (t *Transport) PauseTopic(topic string) error {
+	entry, err := t.lookupSub(topic)
+	if err != nil {
+		return err
+	}
+	if entry.setPaused(true) {
+		entry.setInFlight(0)
+		if t.opts.Logger != nil {
+			t.opts.Logger.Info("subscription paused: %s", topic)
+		}
+	}
+	return nil
+}
+
+// ResumeTopic undoes PauseTopic.
+func (t *Transport) ResumeTopic(topic string) error {
+	entry, err := t.lookupSub(topic)
+	if err != nil {
+		return err
+	}
+	if entry.setPaused(false) {
+		entry.setInFlight(defaultMaxInFlight)
+		if t.opts.Logger != nil {
+			t.opts.Logger.Info("subscription resumed: %s", topic)
+		}
+	}
+	return nil
+}
+
+// DrainTopic pauses topic, waits up to timeout for in-flight handlers to
+// finish and then removes the subscription. On timeout the subscription is
+// still removed and ErrDrainTimeout is returned. A zero timeout waits
+// indefinitely.
+func (t *Transport) DrainTopic(topic string, timeout time.Duration) error {
+	if err := t.PauseTopic(topic); err != nil {
+		return err
+	}
+	entry, err := t.lookupSub(topic)
+	if err != nil {
+		return err
+	}
+
+	var waitErr error
+	if !waitTimeout(entry.active.Wait, timeout) {
+		waitErr = fmt.Errorf("%w: %s (%d in flight)", ErrDrainTimeout, topic, entry.inFlight.Load())
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.forgetSub(topic)
+	if t.sub == entry.sub {
+		t.sub = nil
+	}
+	if err := t.releaseSub(topic, entry.sub); err != nil {
+		return err
+	}
+	if t.opts.Logger != nil {
+		t.opts.Logger.Info("subscription drained: %s", topic)
+	}
+	return waitErr
+}
+
+// releaseSub stops sub and lets the conn forget the consumer so the topic
+// can be subscribed again. Caller must hold t.mu.
+func (t *Transport) releaseSub(topic string, sub *Subscription) error {
+	if r, ok := t.conn.(interface{ Unsubscribe(string) error }); ok {
+		return r.Unsubscribe(topic)
+	}
+	if sub != nil {
+		return sub.cancel()
+	}
+	return nil
+}
+
+// waitTimeout runs wait and reports whether it returned within timeout.
+// A zero timeout waits indefinitely.
+func waitTimeout(wait func(), timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	if timeout <= 0 {
+		<-done
+		return true
+	}
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
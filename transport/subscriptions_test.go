@@ -0,0 +1,175 @@
+// file: mini/transport/subscriptions_test.go
+package transport
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handlerConn keeps subscribed handlers so tests can push messages.
+type handlerConn struct {
+	mockConn
+	hmu      sync.Mutex
+	handlers map[string]MsgHandler
+}
+
+func (c *handlerConn) Subscribe(subject string, handler MsgHandler) (*Subscription, error) {
+	c.hmu.Lock()
+	defer c.hmu.Unlock()
+	if c.handlers == nil {
+		c.handlers = make(map[string]MsgHandler)
+	}
+	c.handlers[subject] = handler
+	return &Subscription{topic: subject, channel: "test"}, nil
+}
+
+func (c *handlerConn) push(subject string, data []byte) error {
+	c.hmu.Lock()
+	h := c.handlers[subject]
+	c.hmu.Unlock()
+	return h(data)
+}
+
+func TestTransport_ListSubscriptions(t *testing.T) {
+	tr := New(Subject("svc.main"))
+	conn := &handlerConn{}
+	tr.conn = conn
+	tr.SetHandler(func([]byte) error { return nil })
+
+	assert.NoError(t, tr.Subscribe())
+	assert.NoError(t, tr.SubscribePrefix("foo.", func([]byte) error { return nil }))
+
+	subs := tr.ListSubscriptions()
+	assert.Len(t, subs, 3)
+	assert.Equal(t, "foo.a", subs[0].Topic)
+	assert.Equal(t, "svc.main", subs[2].Topic)
+	assert.Equal(t, "test", subs[2].Channel)
+
+	assert.NoError(t, conn.push("svc.main", []byte(`{}`)))
+	assert.Equal(t, int64(1), tr.ListSubscriptions()[2].Delivered)
+
+	assert.NoError(t, tr.Unsubscribe())
+	assert.Len(t, tr.ListSubscriptions(), 2)
+}
+
+func TestTransport_PauseResumeTopic(t *testing.T) {
+	tr := New()
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	var got int
+	assert.NoError(t, tr.SubscribeTopic("jobs", func([]byte) error { got++; return nil }))
+
+	assert.NoError(t, tr.PauseTopic("jobs"))
+	assert.True(t, tr.ListSubscriptions()[0].Paused)
+	assert.ErrorIs(t, conn.push("jobs", []byte(`{}`)), ErrTopicPaused)
+	assert.Equal(t, 0, got)
+
+	assert.NoError(t, tr.ResumeTopic("jobs"))
+	assert.NoError(t, conn.push("jobs", []byte(`{}`)))
+	assert.Equal(t, 1, got)
+
+	assert.ErrorIs(t, tr.PauseTopic("missing"), ErrNotSubscribed)
+	assert.ErrorIs(t, tr.ResumeTopic("missing"), ErrNotSubscribed)
+}
+
+func TestTransport_DrainTopic(t *testing.T) {
+	tr := New()
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.NoError(t, tr.SubscribeTopic("slow", func([]byte) error {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	go func() { _ = conn.push("slow", []byte(`{}`)) }()
+	<-started
+	assert.Equal(t, int64(1), tr.ListSubscriptions()[0].InFlight)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	assert.NoError(t, tr.DrainTopic("slow", time.Second))
+	assert.Empty(t, tr.ListSubscriptions())
+	assert.ErrorIs(t, tr.DrainTopic("slow", time.Second), ErrNotSubscribed)
+}
+
+func TestTransport_DrainTopicTimeout(t *testing.T) {
+	tr := New()
+	conn := &handlerConn{}
+	tr.conn = conn
+
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{})
+	assert.NoError(t, tr.SubscribeTopic("stuck", func([]byte) error {
+		close(started)
+		<-block
+		return nil
+	}))
+
+	go func() { _ = conn.push("stuck", []byte(`{}`)) }()
+	<-started
+
+	err := tr.DrainTopic("stuck", 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrDrainTimeout)
+	assert.Empty(t, tr.ListSubscriptions())
+}
+
+func TestTopicSub_NoHandlerAdmittedAfterPause(t *testing.T) {
+	for round := 0; round < 50; round++ {
+		entry := &topicSub{}
+		var started, running sync.WaitGroup
+		var afterWait int64
+		var mu sync.Mutex
+		waited := false
+
+		h := entry.gate(func([]byte) error {
+			mu.Lock()
+			if waited {
+				afterWait++
+			}
+			mu.Unlock()
+			return nil
+		})
+
+		stop := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			started.Add(1)
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				started.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						_ = h(nil)
+					}
+				}
+			}()
+		}
+		started.Wait()
+
+		entry.setPaused(true)
+		entry.active.Wait()
+		mu.Lock()
+		waited = true
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+		close(stop)
+		running.Wait()
+		assert.Zero(t, afterWait, "handler admitted after drain wait returned")
+		assert.Zero(t, entry.inFlight.Load())
+	}
+}
@@ -0,0 +1,241 @@
+// file: mini/transport/tracecontext.go
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+)
+
+// ----------------------------------------------------
+// W3C Trace Context (traceparent) and Baggage
+// ----------------------------------------------------
+//
+// Headers follow https://www.w3.org/TR/trace-context/ and
+// https://www.w3.org/TR/baggage/ so traces can be joined with any
+// OpenTelemetry-instrumented peer without linking the SDK here.
+// New traces use the W3C trace ID as the message trace_id; spans are
+// reported through ISpanExporter (see span.go).
+
+var ErrInvalidTraceparent = errors.New("transport: invalid traceparent")
+
+// TraceParent is a parsed traceparent header (version 00).
+type TraceParent struct {
+	TraceID string // 32 lowercase hex chars
+	SpanID  string // 16 lowercase hex chars
+	Sampled bool
+}
+
+// NewTraceParent starts a new sampled trace.
+func NewTraceParent() TraceParent {
+	return TraceParent{TraceID: randomHex(16), SpanID: randomHex(8), Sampled: true}
+}
+
+// ParseTraceparent parses a "00-<trace>-<span>-<flags>" header.
+func ParseTraceparent(s string) (TraceParent, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || parts[0] == "ff" || len(parts[0]) != 2 {
+		return TraceParent{}, ErrInvalidTraceparent
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return TraceParent{}, ErrInvalidTraceparent
+	}
+	tp := TraceParent{TraceID: parts[1], SpanID: parts[2]}
+	if !isHexID(tp.TraceID, 32) || !isHexID(tp.SpanID, 16) || !isHexID(parts[3], 2) {
+		return TraceParent{}, ErrInvalidTraceparent
+	}
+	flags, _ := hex.DecodeString(parts[3])
+	tp.Sampled = flags[0]&0x01 == 1
+	return tp, nil
+}
+
+// String formats the header value.
+func (tp TraceParent) String() string {
+	flags := "00"
+	if tp.Sampled {
+		flags = "01"
+	}
+	return "00-" + tp.TraceID + "-" + tp.SpanID + "-" + flags
+}
+
+// IsValid reports whether both IDs are well-formed and non-zero.
+func (tp TraceParent) IsValid() bool {
+	return isHexID(tp.TraceID, 32) && isHexID(tp.SpanID, 16)
+}
+
+// Child returns a new span in the same trace.
+func (tp TraceParent) Child() TraceParent {
+	return TraceParent{TraceID: tp.TraceID, SpanID: randomHex(8), Sampled: tp.Sampled}
+}
+
+// Baggage holds W3C baggage members.
+type Baggage map[string]string
+
+// ParseBaggage parses a "k1=v1,k2=v2;prop" header. Member properties are
+// dropped and malformed members are skipped.
+func ParseBaggage(s string) Baggage {
+	b := Baggage{}
+	for _, member := range strings.Split(s, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		k, v, ok := strings.Cut(member, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		if dv, err := url.PathUnescape(strings.TrimSpace(v)); err == nil {
+			b[k] = dv
+		}
+	}
+	return b
+}
+
+// String formats the header value with keys sorted.
+func (b Baggage) String() string {
+	keys := make([]string, 0, len(b))
+	for k := range b {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+url.PathEscape(b[k]))
+	}
+	return strings.Join(parts, ",")
+}
+
+type traceParentKey struct{}
+type baggageKey struct{}
+
+// WithTraceParent stores the current span in ctx.
+func WithTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, tp)
+}
+
+// TraceParentFromContext returns the current span stored in ctx.
+func TraceParentFromContext(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentKey{}).(TraceParent)
+	return tp, ok
+}
+
+// WithBaggage stores baggage in ctx.
+func WithBaggage(ctx context.Context, b Baggage) context.Context {
+	return context.WithValue(ctx, baggageKey{}, b)
+}
+
+// BaggageFromContext returns the baggage stored in ctx, or nil.
+func BaggageFromContext(ctx context.Context) Baggage {
+	b, _ := ctx.Value(baggageKey{}).(Baggage)
+	return b
+}
+
+// ApplyTraceContext stamps traceparent and baggage headers onto msg.
+// A missing trace_id is filled from ctx or the traceparent trace ID. The
+// traceparent trace ID always follows trace_id (see W3CTraceID): the
+// outgoing span is a child of the span in ctx when both belong to that
+// trace, otherwise a new trace is started. Headers already present on msg
+// are kept.
+func ApplyTraceContext(ctx context.Context, msg codec.IMessage) {
+	traceID := msg.GetString("trace_id")
+	if traceID == "" {
+		traceID = contextTraceID(ctx)
+	}
+	if msg.GetHeader(constant.HeaderTraceparent) == "" {
+		want := W3CTraceID(traceID)
+		var tp TraceParent
+		if parent, ok := TraceParentFromContext(ctx); ok && parent.IsValid() && (want == "" || want == parent.TraceID) {
+			tp = parent.Child()
+		} else {
+			tp = NewTraceParent()
+			if want != "" {
+				tp.TraceID = want
+			}
+		}
+		msg.SetHeader(constant.HeaderTraceparent, tp.String())
+	}
+	if msg.GetString("trace_id") == "" {
+		if traceID == "" {
+			tp, _ := ParseTraceparent(msg.GetHeader(constant.HeaderTraceparent))
+			traceID = tp.TraceID
+		}
+		if traceID != "" {
+			msg.Set("trace_id", traceID)
+		}
+	}
+	if msg.GetHeader(constant.HeaderBaggage) == "" {
+		if b := BaggageFromContext(ctx); len(b) > 0 {
+			msg.SetHeader(constant.HeaderBaggage, b.String())
+		}
+	}
+}
+
+// W3CTraceID maps a message trace_id to the traceparent trace ID: valid
+// W3C IDs are used as is, legacy IDs (e.g. UUIDs) are hashed so every hop
+// derives the same trace.
+func W3CTraceID(traceID string) string {
+	if traceID == "" || isHexID(traceID, 32) {
+		return traceID
+	}
+	sum := sha256.Sum256([]byte(traceID))
+	return hex.EncodeToString(sum[:16])
+}
+
+// contextTraceID returns the trace_id for messages sent with ctx: the one
+// stored with WithTrace when it matches the current span's trace,
+// otherwise the span's trace ID.
+func contextTraceID(ctx context.Context) string {
+	traceID := TraceIDFromContext(ctx)
+	if parent, ok := TraceParentFromContext(ctx); ok && parent.IsValid() {
+		if traceID == "" || W3CTraceID(traceID) != parent.TraceID {
+			return parent.TraceID
+		}
+	}
+	return traceID
+}
+
+// traceContextFromMessage adds the message span and baggage to ctx.
+func traceContextFromMessage(ctx context.Context, msg codec.IMessage) context.Context {
+	if tp, err := ParseTraceparent(msg.GetHeader(constant.HeaderTraceparent)); err == nil {
+		ctx = WithTraceParent(ctx, tp)
+	}
+	if h := msg.GetHeader(constant.HeaderBaggage); h != "" {
+		ctx = WithBaggage(ctx, ParseBaggage(h))
+	}
+	return ctx
+}
+
+// newTraceID returns a random W3C trace ID.
+func newTraceID() string {
+	return randomHex(16)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// isHexID reports whether s is n lowercase hex chars and not all zeros.
+func isHexID(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	zero := true
+	for _, c := range s {
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			zero = false
+		default:
+			return false
+		}
+	}
+	return !zero || n == 2
+}
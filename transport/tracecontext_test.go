@@ -0,0 +1,143 @@
+// file: mini/transport/tracecontext_test.go
+package transport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tp, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tp.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", tp.SpanID)
+	assert.True(t, tp.Sampled)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tp.String())
+
+	for _, bad := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(bad)
+		assert.ErrorIs(t, err, ErrInvalidTraceparent, bad)
+	}
+
+	child := tp.Child()
+	assert.Equal(t, tp.TraceID, child.TraceID)
+	assert.NotEqual(t, tp.SpanID, child.SpanID)
+}
+
+func TestBaggage_RoundTrip(t *testing.T) {
+	b := ParseBaggage("tenant=acme, user=a%20b;prop=1, bad")
+	assert.Equal(t, Baggage{"tenant": "acme", "user": "a b"}, b)
+	assert.Equal(t, "tenant=acme,user=a%20b", b.String())
+}
+
+func TestPublish_PropagatesTraceContext(t *testing.T) {
+	conn := &dlqConn{}
+	tr := New()
+	tr.conn = conn
+
+	// inbound message from an instrumented peer
+	in := codec.NewMessage("request")
+	in.SetHeader(constant.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	in.SetHeader(constant.HeaderBaggage, "tenant=acme")
+	ctx := ContextFromMessage(context.Background(), in)
+
+	out := codec.NewMessage("event")
+	ApplyTraceContext(ctx, out)
+	data, _ := codec.Marshal(out)
+	assert.NoError(t, tr.Publish("orders", data))
+
+	got := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(conn.published["orders"], got))
+	tp, err := ParseTraceparent(got.GetHeader(constant.HeaderTraceparent))
+	assert.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tp.TraceID)
+	assert.NotEqual(t, "00f067aa0ba902b7", tp.SpanID)
+	assert.Equal(t, tp.TraceID, got.GetString("trace_id"))
+	assert.Equal(t, "tenant=acme", got.GetHeader(constant.HeaderBaggage))
+}
+
+func TestPublish_NewTraceMatchesTraceID(t *testing.T) {
+	conn := &dlqConn{}
+	tr := New()
+	tr.conn = conn
+
+	data, _ := codec.Marshal(codec.NewMessage("event"))
+	assert.NoError(t, tr.Publish("orders", data))
+
+	got := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(conn.published["orders"], got))
+	tp, err := ParseTraceparent(got.GetHeader(constant.HeaderTraceparent))
+	assert.NoError(t, err)
+	assert.Equal(t, got.GetString("trace_id"), tp.TraceID)
+}
+
+func TestApplyTraceContext_LegacyTraceID(t *testing.T) {
+	legacy := "6f1c2a6e-legacy-trace"
+
+	first := codec.NewMessage("request")
+	first.Set("trace_id", legacy)
+	ApplyTraceContext(context.Background(), first)
+	tp, err := ParseTraceparent(first.GetHeader(constant.HeaderTraceparent))
+	assert.NoError(t, err)
+	assert.Equal(t, W3CTraceID(legacy), tp.TraceID)
+
+	// the next hop keeps both IDs in the same trace
+	next := codec.NewMessage("event")
+	ApplyTraceContext(ContextFromMessage(context.Background(), first), next)
+	child, err := ParseTraceparent(next.GetHeader(constant.HeaderTraceparent))
+	assert.NoError(t, err)
+	assert.Equal(t, tp.TraceID, child.TraceID)
+	assert.NotEqual(t, tp.SpanID, child.SpanID)
+	assert.Equal(t, legacy, next.GetString("trace_id"))
+
+	// an explicit trace_id from another trace starts a matching root span
+	other := codec.NewMessage("event")
+	other.Set("trace_id", "other-trace")
+	ApplyTraceContext(ContextFromMessage(context.Background(), first), other)
+	root, _ := ParseTraceparent(other.GetHeader(constant.HeaderTraceparent))
+	assert.Equal(t, W3CTraceID("other-trace"), root.TraceID)
+}
+
+func TestRequest_ExportsProducerSpan(t *testing.T) {
+	var spans []Span
+	log := &testLogger{}
+	tr := New(WithSpanExporter(SpanExporterFunc(func(s Span) { spans = append(spans, s) })), WithLogger(log))
+	tr.conn = &mockIConn{}
+
+	in := codec.NewMessage("request")
+	in.SetHeader(constant.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextFromMessage(context.Background(), in)
+
+	data, _ := codec.Marshal(codec.NewMessage("request"))
+	assert.NoError(t, tr.RequestWithContext(ctx, "orders.get", data, nil))
+
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "publish orders.get", spans[0].Name)
+	assert.Equal(t, SpanKindProducer, spans[0].Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanID)
+	assert.NotEqual(t, spans[0].ParentSpanID, spans[0].SpanID)
+	assert.Empty(t, spans[0].Err)
+	assert.Empty(t, log.debug, "routing is only logged with WithDebug")
+}
+
+func TestTraceMiddleware_DebugLogsThroughLogger(t *testing.T) {
+	log := &testLogger{}
+	tr := New(WithLogger(log), WithDebug())
+	tr.conn = &dlqConn{}
+
+	data, _ := codec.Marshal(codec.NewMessage("event"))
+	assert.NoError(t, tr.Publish("orders", data))
+	assert.Len(t, log.debug, 1)
+	assert.Contains(t, log.debug[0], "orders")
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/rskv-p/mini/codec"
+	"github.com/rskv-p/mini/logger"
 )
 
 var (
@@ -56,6 +57,12 @@ type Transport struct {
 	mu          sync.Mutex
 	middlewares []MiddlewareFunc
 	active      sync.WaitGroup
+
+	subsMu sync.RWMutex
+	subs   map[string]*topicSub
+
+	sealMu        sync.Mutex
+	sealedReplies map[string]time.Time // reply subject -> expiry
 }
 
 var _ ITransport = (*Transport)(nil)
@@ -72,7 +79,14 @@ func New(opts ...Option) *Transport {
 		o(&options)
 	}
 	t := &Transport{opts: options}
-	t.Use(TraceMiddleware())
+	var traceLog logger.ILogger
+	if options.Debug {
+		traceLog = options.Logger
+	}
+	t.Use(traceMiddleware(traceLog, options.SpanExporter))
+	if options.Recorder != nil {
+		t.Use(options.Recorder.middleware(options.Logger))
+	}
 	return t
 }
 
@@ -110,16 +124,30 @@ func (t *Transport) Init() error {
 	return nil
 }
 
+// Close stops all consumers and the producer. It waits for running handlers
+// before closing the connection so their replies still go out. With
+// WithDrainTimeout set it drains first and does not wait past the deadline;
+// handlers still running then get ErrDisconnected when they publish.
 func (t *Transport) Close() error {
+	if t.opts.DrainTimeout > 0 {
+		if _, err := t.Drain(t.opts.DrainTimeout); err != nil && t.opts.Logger != nil {
+			t.opts.Logger.Warn("close: %v", err)
+		}
+	} else {
+		// Not under t.mu: handlers take it to publish.
+		t.active.Wait()
+	}
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.active.Wait()
-
 	if t.sub != nil {
 		_ = t.sub.cancel()
 		t.sub = nil
 	}
+	t.subsMu.Lock()
+	t.subs = nil
+	t.subsMu.Unlock()
 	if t.conn != nil {
 		t.conn.Close()
 		t.conn = nil
@@ -130,6 +158,13 @@ func (t *Transport) Close() error {
 	return nil
 }
 
+// connection returns the current conn, or nil once the transport is closed.
+func (t *Transport) connection() IConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn
+}
+
 func (t *Transport) Options() Options        { return t.opts }
 func (t *Transport) SetHandler(h MsgHandler) { t.handler = h }
 func (t *Transport) Use(mw MiddlewareFunc)   { t.middlewares = append(t.middlewares, mw) }
@@ -145,6 +180,24 @@ func (t *Transport) wrap(handler TransportHandler) TransportHandler {
 	}
 }
 
+// inbound returns the conn-level handler for topic: messages are unsealed
+// and verified, then passed through the middleware chain to handler.
+func (t *Transport) inbound(topic string, handler MsgHandler) MsgHandler {
+	h := t.wrap(func(ctx context.Context, subject string, data []byte) error {
+		data, err := t.openData(subject, data)
+		if err != nil {
+			return err
+		}
+		if err := t.verifyData(subject, data); err != nil {
+			return err
+		}
+		return handler(data)
+	})
+	return func(data []byte) error {
+		return h(context.Background(), topic, data)
+	}
+}
+
 func (t *Transport) Subscribe() error {
 	if t.handler == nil {
 		return ErrMissingHandler
@@ -168,13 +221,7 @@ func (t *Transport) subscribeInternal(topic string, rawHandler MsgHandler) error
 		t.opts.Logger.Debug("subscribe to: %s", topic)
 	}
 
-	handler := t.wrap(func(ctx context.Context, subject string, data []byte) error {
-		return rawHandler(data)
-	})
-
-	sub, err := t.conn.Subscribe(topic, func(data []byte) error {
-		return handler(context.Background(), topic, data)
-	})
+	sub, err := t.subscribeTracked(topic, t.inbound(topic, rawHandler))
 	if err != nil {
 		return err
 	}
@@ -189,6 +236,7 @@ func (t *Transport) Unsubscribe() error {
 	defer t.mu.Unlock()
 
 	if t.sub != nil {
+		t.forgetSub(t.opts.Subject)
 		err := t.sub.cancel()
 		t.sub = nil
 		return err
@@ -203,10 +251,11 @@ func (t *Transport) IsConnected() bool {
 }
 
 func (t *Transport) Ping() error {
-	if t.conn == nil {
+	conn := t.connection()
+	if conn == nil {
 		return ErrDisconnected
 	}
-	return t.conn.Ping()
+	return conn.Ping()
 }
 
 func (t *Transport) Health() error {
@@ -261,12 +310,7 @@ func (t *Transport) SubscribePrefix(prefix string, handler MsgHandler) error {
 
 	for _, topic := range topics {
 		if strings.HasPrefix(topic, prefix) {
-			h := t.wrap(func(ctx context.Context, subject string, data []byte) error {
-				return handler(data)
-			})
-			_, err := t.conn.Subscribe(topic, func(data []byte) error {
-				return h(context.Background(), topic, data)
-			})
+			_, err := t.subscribeTracked(topic, t.inbound(topic, handler))
 			if err != nil && t.opts.Logger != nil {
 				t.opts.Logger.Warn("prefix subscribe failed for %s: %v", topic, err)
 			}
@@ -42,6 +42,16 @@ type Options struct {
 	OnFailure         func(subject string, err error)
 	RetryPolicies     map[string]RetryPolicy
 	DeadLetterHandler func(subject string, data []byte, err error)
+	DeadLetterSubject string
+	DeadLetterSinks   []IDeadLetterSink
+	Keyring           *Keyring
+	SealSubjects      []string
+	Signer            *Signer
+	Verifier          *KeySet
+	VerifySubjects    []string
+	Recorder          *Recorder
+	DrainTimeout      time.Duration
+	SpanExporter      ISpanExporter
 }
 
 // Option is a function that applies a configuration change.
@@ -135,6 +145,71 @@ func WithDeadLetterHandler(handler func(subject string, data []byte, err error))
 	}
 }
 
+// WithDeadLetterSubject republishes final delivery failures to a DLQ subject.
+func WithDeadLetterSubject(subject string) Option {
+	return func(o *Options) {
+		o.DeadLetterSubject = subject
+	}
+}
+
+// WithDeadLetterSink adds a sink that receives final delivery failures.
+func WithDeadLetterSink(sink IDeadLetterSink) Option {
+	return func(o *Options) {
+		o.DeadLetterSinks = append(o.DeadLetterSinks, sink)
+	}
+}
+
+// WithSealing encrypts message bodies with the keyring's active key.
+// With no subjects every outgoing message is sealed; sealed incoming
+// messages are always opened.
+func WithSealing(k *Keyring, subjects ...string) Option {
+	return func(o *Options) {
+		o.Keyring = k
+		o.SealSubjects = subjects
+	}
+}
+
+// WithSigner signs every outgoing message with the publisher key.
+func WithSigner(s *Signer) Option {
+	return func(o *Options) {
+		o.Signer = s
+	}
+}
+
+// WithVerification rejects unsigned or invalid messages received on the
+// given subjects (trailing "*" matches a prefix, e.g. "cfg.*"). With no
+// subjects every incoming message is verified.
+func WithVerification(keys *KeySet, subjects ...string) Option {
+	return func(o *Options) {
+		o.Verifier = keys
+		o.VerifySubjects = subjects
+	}
+}
+
+// WithRecorder installs r.Middleware at New, capturing outbound requests
+// and publishes (see Replay). Write errors are logged to Logger.
+func WithRecorder(r *Recorder) Option {
+	return func(o *Options) {
+		o.Recorder = r
+	}
+}
+
+// WithDrainTimeout makes Close drain subscriptions first, waiting at most d
+// for running handlers (see Drain).
+func WithDrainTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.DrainTimeout = d
+	}
+}
+
+// WithSpanExporter reports a producer span for every outbound message
+// (see Span).
+func WithSpanExporter(e ISpanExporter) Option {
+	return func(o *Options) {
+		o.SpanExporter = e
+	}
+}
+
 // ----------------------------------------------------
 // Defaults and env-based config
 // ----------------------------------------------------
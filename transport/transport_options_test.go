@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -27,9 +28,12 @@ func (m *testMetrics) AddLatency(name string, ms int64) {
 
 type testLogger struct {
 	fields map[string]any
+	debug  []string
 }
 
-func (l *testLogger) Debug(msg string, args ...any) {}
+func (l *testLogger) Debug(msg string, args ...any) {
+	l.debug = append(l.debug, fmt.Sprintf(msg, args...))
+}
 func (l *testLogger) Info(msg string, args ...any)  {}
 func (l *testLogger) Warn(msg string, args ...any)  {}
 func (l *testLogger) Error(msg string, args ...any) {}
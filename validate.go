@@ -0,0 +1,265 @@
+// file: mini/validate.go
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rskv-p/mini/codec"
+)
+
+// ----------------------------------------------------
+// Schema types
+// ----------------------------------------------------
+
+// Supported InputSchemaField types.
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeNumber = "number"
+	TypeBool   = "bool"
+	TypeObject = "object"
+	TypeArray  = "array"
+	TypeAny    = "any"
+
+	// Canonical string encodings, see codec.SetTime / SetDuration / SetDecimal.
+	TypeTime     = "time"
+	TypeDuration = "duration"
+	TypeDecimal  = "decimal"
+)
+
+// ----------------------------------------------------
+// Validation errors
+// ----------------------------------------------------
+
+// FieldError describes a single invalid input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects all field errors for one request.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ----------------------------------------------------
+// Validation and coercion
+// ----------------------------------------------------
+
+// validateInput checks input against schema, fills defaults and coerces
+// values to the declared types in place. Unknown fields are left untouched.
+func validateInput(schema []InputSchemaField, input map[string]any) error {
+	var errs []FieldError
+	for _, f := range schema {
+		val, exists := input[f.Name]
+		if !exists || isEmpty(val) {
+			if f.Default != nil {
+				input[f.Name] = f.Default
+				continue
+			}
+			if f.Required {
+				errs = append(errs, FieldError{
+					Field:   f.Name,
+					Code:    "required",
+					Message: "missing required field: " + f.Name,
+				})
+			}
+			continue
+		}
+
+		coerced, ok := coerce(f.Type, val)
+		if !ok {
+			errs = append(errs, FieldError{
+				Field:   f.Name,
+				Code:    "type",
+				Message: fmt.Sprintf("field %s: expected %s, got %T", f.Name, f.Type, val),
+			})
+			continue
+		}
+		input[f.Name] = coerced
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// ----------------------------------------------------
+// Custom types
+// ----------------------------------------------------
+
+var (
+	inputTypes   = map[string]func(any) (any, bool){}
+	inputTypesMu sync.RWMutex
+)
+
+// RegisterInputType adds a schema type such as "uuid" or "email"; coerce
+// checks and converts each value. Names are case-insensitive and must not
+// shadow a built-in type.
+func RegisterInputType(name string, coerce func(v any) (any, bool)) {
+	name = strings.ToLower(name)
+	if builtinType(name) {
+		panic("service: input type " + name + " is built in")
+	}
+	inputTypesMu.Lock()
+	defer inputTypesMu.Unlock()
+	inputTypes[name] = coerce
+}
+
+func customType(typ string) (func(any) (any, bool), bool) {
+	inputTypesMu.RLock()
+	defer inputTypesMu.RUnlock()
+	fn, ok := inputTypes[strings.ToLower(typ)]
+	return fn, ok
+}
+
+func builtinType(typ string) bool {
+	switch strings.ToLower(typ) {
+	case "", TypeAny, TypeString, TypeInt, "integer", TypeNumber, "float", TypeBool, "boolean",
+		TypeObject, "map", TypeArray, TypeTime, TypeDuration, TypeDecimal:
+		return true
+	}
+	return false
+}
+
+// unknownTypes returns the fields of schema whose type is neither built in
+// nor registered with RegisterInputType.
+func unknownTypes(schema []InputSchemaField) []string {
+	var out []string
+	for _, f := range schema {
+		if _, ok := customType(f.Type); !ok && !builtinType(f.Type) {
+			out = append(out, f.Name+" ("+f.Type+")")
+		}
+	}
+	return out
+}
+
+// coerce converts v to the given schema type. Integers stay float64, the
+// type handlers get from encoding/json. Types registered with
+// RegisterInputType use their own coerce; unknown types are passed through
+// unchanged (RegisterAction warns about them).
+func coerce(typ string, v any) (any, bool) {
+	switch strings.ToLower(typ) {
+	case "", TypeAny:
+		return v, true
+	case TypeString:
+		switch x := v.(type) {
+		case string:
+			return x, true
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(x), true
+		}
+	case TypeInt, "integer":
+		switch x := v.(type) {
+		case float64:
+			if x == math.Trunc(x) {
+				return x, true
+			}
+		case int:
+			return float64(x), true
+		case int64:
+			return float64(x), true
+		case string:
+			if i, err := strconv.ParseInt(x, 10, 64); err == nil {
+				return float64(i), true
+			}
+		}
+	case TypeNumber, "float":
+		switch x := v.(type) {
+		case float64:
+			return x, true
+		case int:
+			return float64(x), true
+		case int64:
+			return float64(x), true
+		case string:
+			if f, err := strconv.ParseFloat(x, 64); err == nil {
+				return f, true
+			}
+		}
+	case TypeBool, "boolean":
+		switch x := v.(type) {
+		case bool:
+			return x, true
+		case string:
+			if b, err := strconv.ParseBool(x); err == nil {
+				return b, true
+			}
+		}
+	case TypeObject, "map":
+		if m, ok := v.(map[string]any); ok {
+			return m, true
+		}
+	case TypeArray:
+		if a, ok := v.([]any); ok {
+			return a, true
+		}
+	case TypeTime:
+		if t, ok := codec.TimeValue(v); ok {
+			return t.Format(codec.TimeLayout), true
+		}
+	case TypeDuration:
+		if d, ok := codec.DurationValue(v); ok {
+			return d.String(), true
+		}
+	case TypeDecimal:
+		if d, ok := codec.DecimalValue(v); ok {
+			return d.String(), true
+		}
+	default:
+		if fn, ok := customType(typ); ok {
+			return fn(v)
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+// openAPIType maps a schema type to its OpenAPI name, or "" when the type
+// does not constrain the JSON type ("any", custom and unknown types).
+func openAPIType(typ string) string {
+	switch strings.ToLower(typ) {
+	case TypeInt, "integer":
+		return "integer"
+	case TypeNumber, "float":
+		return "number"
+	case TypeBool, "boolean":
+		return "boolean"
+	case TypeObject, "map":
+		return "object"
+	case TypeTime, TypeDuration, TypeDecimal, TypeString:
+		return "string"
+	case TypeArray:
+		return "array"
+	}
+	return ""
+}
+
+// openAPIFormat returns the OpenAPI format hint for a schema type, if any.
+func openAPIFormat(typ string) string {
+	switch strings.ToLower(typ) {
+	case TypeTime:
+		return "date-time"
+	case TypeDuration, TypeDecimal:
+		return strings.ToLower(typ)
+	}
+	if _, ok := customType(typ); ok {
+		return strings.ToLower(typ)
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+// file: mini/validate_test.go
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rskv-p/mini/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateInput_DefaultsAndCoercion(t *testing.T) {
+	schema := []InputSchemaField{
+		{Name: "name", Type: TypeString, Required: true},
+		{Name: "age", Type: TypeInt},
+		{Name: "score", Type: TypeNumber},
+		{Name: "active", Type: TypeBool},
+		{Name: "limit", Type: TypeInt, Default: int64(10)},
+	}
+	in := map[string]any{
+		"name":   "bob",
+		"age":    "42",
+		"score":  "1.5",
+		"active": "true",
+		"extra":  "kept",
+	}
+
+	assert.NoError(t, validateInput(schema, in))
+	assert.Equal(t, float64(42), in["age"])
+	assert.Equal(t, 1.5, in["score"])
+	assert.Equal(t, true, in["active"])
+	assert.Equal(t, int64(10), in["limit"])
+	assert.Equal(t, "kept", in["extra"])
+}
+
+func TestValidateInput_Errors(t *testing.T) {
+	schema := []InputSchemaField{
+		{Name: "id", Type: TypeInt, Required: true},
+		{Name: "age", Type: TypeInt},
+		{Name: "tags", Type: TypeArray},
+	}
+	in := map[string]any{"age": 1.5, "tags": "nope"}
+
+	err := validateInput(schema, in)
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, verr.Fields, 3)
+	assert.Equal(t, "required", verr.Fields[0].Code)
+	assert.Equal(t, "type", verr.Fields[1].Code)
+	assert.Equal(t, "tags", verr.Fields[2].Field)
+	assert.Contains(t, err.Error(), "missing required field: id")
+}
+
+func TestValidation_TypeMismatchResponse(t *testing.T) {
+	tr := &stubTransport{}
+	s := newInitService(Transport(tr))
+	called := false
+
+	s.RegisterAction("test.typed", []InputSchemaField{
+		{Name: "count", Type: TypeInt, Required: true},
+	}, func(ctx context.Context, in map[string]any) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	msg := codec.NewMessage("")
+	msg.SetContextID("ctx-typed")
+	msg.SetNode("test.typed")
+	msg.Set("count", "abc")
+
+	err := s.prepareHandler(s.actions["test.typed"].handler)(context.Background(), msg, "reply.typed")
+	assert.NotNil(t, err)
+	assert.Equal(t, 400, err.StatusCode)
+	assert.False(t, called)
+
+	assert.Len(t, tr.sent, 1)
+	resp := codec.NewMessage("")
+	assert.NoError(t, codec.Unmarshal(tr.sent[0].data, resp))
+	assert.Equal(t, "reply.typed", tr.sent[0].subject)
+	_, ok := resp.Get("fields")
+	assert.True(t, ok)
+}
+
+func TestValidation_IntStaysFloat64AndUnknownTypesPass(t *testing.T) {
+	s := newInitService()
+	var got map[string]any
+	s.RegisterAction("test.ok", []InputSchemaField{
+		{Name: "count", Type: TypeInt, Required: true},
+		{Name: "id", Type: "uuid", Required: true},
+	}, func(ctx context.Context, in map[string]any) (any, error) {
+		got = in
+		return nil, nil
+	})
+
+	msg := codec.NewMessage("")
+	msg.SetNode("test.ok")
+	msg.Set("count", float64(3))
+	msg.Set("id", "4f7c2a9e-0000-4000-8000-000000000000")
+
+	assert.Nil(t, s.prepareHandler(s.actions["test.ok"].handler)(context.Background(), msg, "reply.ok"))
+	assert.Equal(t, float64(3), got["count"])
+	assert.Equal(t, "4f7c2a9e-0000-4000-8000-000000000000", got["id"])
+}
+
+func TestOpenAPIType(t *testing.T) {
+	assert.Equal(t, "integer", openAPIType(TypeInt))
+	assert.Equal(t, "boolean", openAPIType(TypeBool))
+	assert.Equal(t, "string", openAPIType(TypeString))
+	assert.Equal(t, "", openAPIType(TypeAny))
+	assert.Equal(t, "", openAPIType("uuid"))
+
+	s := newInitService()
+	s.RegisterAction("test.any", []InputSchemaField{{Name: "v", Type: TypeAny}}, nil)
+	schema := s.GetOpenAPISchemas()["test.any"].(map[string]any)
+	assert.Equal(t, map[string]any{}, schema["properties"].(map[string]any)["v"])
+}
+
+func TestRegisterInputType(t *testing.T) {
+	RegisterInputType("test-code", func(v any) (any, bool) {
+		s, ok := v.(string)
+		return strings.ToUpper(s), ok && len(s) == 3
+	})
+	t.Cleanup(func() {
+		inputTypesMu.Lock()
+		delete(inputTypes, "test-code")
+		inputTypesMu.Unlock()
+	})
+
+	schema := []InputSchemaField{{Name: "code", Type: "test-code"}}
+	in := map[string]any{"code": "abc"}
+	assert.NoError(t, validateInput(schema, in))
+	assert.Equal(t, "ABC", in["code"])
+	assert.Error(t, validateInput(schema, map[string]any{"code": "abcd"}))
+	assert.Equal(t, "test-code", openAPIFormat("test-code"))
+
+	assert.Panics(t, func() { RegisterInputType("Int", nil) })
+}
+
+func TestRegisterAction_WarnsOnUnknownTypes(t *testing.T) {
+	log := &testLogger{}
+	s := newInitService(Logger(log))
+	s.RegisterAction("test.known", []InputSchemaField{{Name: "n", Type: TypeInt}}, nil)
+	assert.Empty(t, log.warns)
+
+	s.RegisterAction("test.unknown", []InputSchemaField{{Name: "id", Type: "uuid"}}, nil)
+	assert.Len(t, log.warns, 1)
+	assert.Contains(t, log.warns[0], "id (uuid)")
+}
+
+func TestInput_TypedAccessors(t *testing.T) {
+	s := newInitService()
+	var (
+		count  int64
+		at     time.Time
+		amount codec.Decimal
+		errs   []error
+	)
+	s.RegisterAction("test.input", []InputSchemaField{
+		{Name: "count", Type: TypeInt, Required: true},
+		{Name: "at", Type: TypeTime},
+		{Name: "amount", Type: TypeDecimal, Default: "1.50"},
+		{Name: "note", Type: TypeString},
+	}, func(ctx context.Context, _ map[string]any) (any, error) {
+		in := InputFrom(ctx)
+		var err error
+		count, err = in.Int("count")
+		errs = append(errs, err)
+		at, err = in.Time("at")
+		errs = append(errs, err)
+		amount, err = in.Decimal("amount")
+		errs = append(errs, err)
+		note, err := in.String("note")
+		errs = append(errs, err)
+		assert.Equal(t, "", note)
+		assert.False(t, in.Has("note"))
+
+		_, err = in.Bool("count")
+		assert.ErrorIs(t, err, ErrInputField)
+		_, err = in.String("missing")
+		assert.ErrorIs(t, err, ErrInputField)
+		return nil, nil
+	})
+
+	msg := codec.NewMessage("")
+	msg.SetNode("test.input")
+	msg.Set("count", "7")
+	msg.Set("at", "2024-05-01T10:00:00Z")
+
+	assert.Nil(t, s.prepareHandler(s.actions["test.input"].handler)(context.Background(), msg, "reply.input"))
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(7), count)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), at.UTC())
+	assert.Equal(t, codec.Decimal("1.50"), amount)
+}
+
+func TestValidateInput_CanonicalTypes(t *testing.T) {
+	schema := []InputSchemaField{
+		{Name: "at", Type: TypeTime},
+		{Name: "ttl", Type: TypeDuration},
+		{Name: "amount", Type: TypeDecimal},
+	}
+	in := map[string]any{
+		"at":     "2024-05-01T10:00:00+02:00",
+		"ttl":    "90s",
+		"amount": "+12.50",
+	}
+
+	assert.NoError(t, validateInput(schema, in))
+	assert.Equal(t, "2024-05-01T10:00:00+02:00", in["at"])
+	assert.Equal(t, "1m30s", in["ttl"])
+	assert.Equal(t, "12.50", in["amount"])
+
+	err := validateInput(schema, map[string]any{"at": "yesterday", "amount": "1,5"})
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, verr.Fields, 2)
+
+	// 0.1+0.2 as a JSON number has already been rounded to float64.
+	err = validateInput(schema, map[string]any{"amount": 0.30000000000000004})
+	verr, ok = err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, verr.Fields, 1)
+
+	assert.Equal(t, "string", openAPIType(TypeDecimal))
+	assert.Equal(t, "date-time", openAPIFormat(TypeTime))
+	assert.Equal(t, "", openAPIFormat(TypeInt))
+}